@@ -3,17 +3,36 @@ package main
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/conductorone/baton-sdk/pkg/cli"
 	"github.com/spf13/cobra"
+
+	"github.com/conductorone/baton-snipe-it/pkg/connector"
 )
 
 // config defines the external configuration required for the connector to run.
 type config struct {
 	cli.BaseConfig `mapstructure:",squash"` // Puts the base config options in the same place as the connector options
 
-	BaseUrl     string `mapstructure:"base-url"`
-	AccessToken string `mapstructure:"access-token"`
+	BaseUrl           string `mapstructure:"base-url"`
+	AccessToken       string `mapstructure:"access-token"`
+	MaxAttempts       int    `mapstructure:"max-attempts"`
+	RequestsPerMinute int    `mapstructure:"requests-per-minute"`
+	MaxConcurrency    int    `mapstructure:"max-concurrency"`
+	Timezone          string `mapstructure:"timezone"`
+
+	GroupMembershipFromUsers bool          `mapstructure:"group-membership-from-users"`
+	IncrementalStateFile     string        `mapstructure:"incremental-state-file"`
+	EffectivePermissions     bool          `mapstructure:"effective-permissions"`
+	PermissionsConfig        string        `mapstructure:"permissions-config"`
+	GrantHistory             bool          `mapstructure:"grant-history"`
+	GrantHistoryLookback     time.Duration `mapstructure:"grant-history-lookback"`
+	ServiceAccountPattern    string        `mapstructure:"service-account-pattern"`
+	ServiceAccountNoLoginAge time.Duration `mapstructure:"service-account-no-login-age"`
+	ServiceAccountGroupAPI   bool          `mapstructure:"service-account-group-api-tokens"`
+	CustomFields             []string      `mapstructure:"custom-fields"`
+	EncryptedCustomFields    bool          `mapstructure:"encrypted-custom-fields"`
 }
 
 // validateConfig is run after the configuration is loaded, and should return an error if it isn't valid.
@@ -24,10 +43,40 @@ func validateConfig(ctx context.Context, cfg *config) error {
 	if cfg.AccessToken == "" {
 		return fmt.Errorf("api-key is required")
 	}
+	if cfg.MaxAttempts < 1 {
+		return fmt.Errorf("max-attempts must be at least 1")
+	}
+	if cfg.RequestsPerMinute < 0 {
+		return fmt.Errorf("requests-per-minute must not be negative")
+	}
+	if cfg.MaxConcurrency < 0 {
+		return fmt.Errorf("max-concurrency must not be negative")
+	}
+	if cfg.ServiceAccountNoLoginAge < 0 {
+		return fmt.Errorf("service-account-no-login-age must not be negative")
+	}
+	if cfg.GrantHistoryLookback < 0 {
+		return fmt.Errorf("grant-history-lookback must not be negative")
+	}
 	return nil
 }
 
 func cmdFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().String("base-url", "", "Base URL for the snipe-it instance")
 	cmd.PersistentFlags().String("access-token", "", "API key for the snipe-it instance")
+	cmd.PersistentFlags().Int("max-attempts", 5, "Maximum number of attempts for a throttled or temporarily unavailable request")
+	cmd.PersistentFlags().Int("requests-per-minute", 0, "Maximum number of requests per minute, defaults to the limit advertised by the snipe-it instance")
+	cmd.PersistentFlags().Int("max-concurrency", 0, "Maximum number of concurrent requests to the snipe-it instance, 0 means unlimited")
+	cmd.PersistentFlags().String("timezone", "UTC", "Timezone of the snipe-it instance, its APP_TIMEZONE, which its timestamps are in")
+	cmd.PersistentFlags().Bool("group-membership-from-users", false, "Compute group memberships in a single pass over the user list instead of listing users per group")
+	cmd.PersistentFlags().Bool("effective-permissions", false, "Grant users the permissions they effectively hold, taking denials, groups and superuser/admin into account")
+	cmd.PersistentFlags().Bool("grant-history", false, "Record who last changed each permission, role and group grant, and when, from the activity log")
+	cmd.PersistentFlags().Duration("grant-history-lookback", connector.DefaultGrantHistoryLookback, "How far back the activity log is read for the grant history")
+	cmd.PersistentFlags().String("permissions-config", "", "Path of a YAML file defining the roles and overriding the risk and sensitivity tags of permissions")
+	cmd.PersistentFlags().String("service-account-pattern", "", "Regular expression matching the usernames of service accounts")
+	cmd.PersistentFlags().Duration("service-account-no-login-age", 0, "Classify users created at least this long ago that never logged in to the web UI as service accounts, 0 disables it")
+	cmd.PersistentFlags().Bool("service-account-group-api-tokens", false, "Also classify users that may create API tokens through one of their groups as service accounts")
+	cmd.PersistentFlags().StringSlice("custom-fields", nil, "Custom fields copied into asset profiles, as field=profile_key pairs matching the field name or database column")
+	cmd.PersistentFlags().Bool("encrypted-custom-fields", false, "Also copy encrypted custom fields into profiles")
+	cmd.PersistentFlags().String("incremental-state-file", "", "Path of a file remembering users between syncs, so only users changed since the previous sync are fetched")
 }
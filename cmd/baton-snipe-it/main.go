@@ -39,7 +39,26 @@ func main() {
 func getConnector(ctx context.Context, cfg *config) (types.ConnectorServer, error) {
 	l := ctxzap.Extract(ctx)
 
-	cb, err := connector.New(ctx, cfg.BaseUrl, cfg.AccessToken)
+	cb, err := connector.New(
+		ctx,
+		cfg.BaseUrl,
+		cfg.AccessToken,
+		connector.WithMaxAttempts(cfg.MaxAttempts),
+		connector.WithRequestsPerMinute(cfg.RequestsPerMinute),
+		connector.WithMaxConcurrency(cfg.MaxConcurrency),
+		connector.WithTimezone(cfg.Timezone),
+		connector.WithGroupMembershipFromUsers(cfg.GroupMembershipFromUsers),
+		connector.WithIncrementalUserSync(cfg.IncrementalStateFile),
+		connector.WithEffectivePermissions(cfg.EffectivePermissions),
+		connector.WithPermissionsConfig(cfg.PermissionsConfig),
+		connector.WithGrantHistory(cfg.GrantHistory),
+		connector.WithGrantHistoryLookback(cfg.GrantHistoryLookback),
+		connector.WithServiceAccountPattern(cfg.ServiceAccountPattern),
+		connector.WithServiceAccountNoLoginAge(cfg.ServiceAccountNoLoginAge),
+		connector.WithServiceAccountGroupAPITokens(cfg.ServiceAccountGroupAPI),
+		connector.WithCustomFields(cfg.CustomFields),
+		connector.WithEncryptedCustomFields(cfg.EncryptedCustomFields),
+	)
 	if err != nil {
 		l.Error("error creating connector", zap.Error(err))
 		return nil, err
@@ -0,0 +1,277 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        (unknown)
+// source: snipeit/v1/entitlement.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Risk is how much damage holding an entitlement allows.
+type Risk int32
+
+const (
+	Risk_RISK_UNSPECIFIED Risk = 0
+	Risk_RISK_LOW         Risk = 1
+	Risk_RISK_MEDIUM      Risk = 2
+	Risk_RISK_HIGH        Risk = 3
+	Risk_RISK_CRITICAL    Risk = 4
+)
+
+// Enum value maps for Risk.
+var (
+	Risk_name = map[int32]string{
+		0: "RISK_UNSPECIFIED",
+		1: "RISK_LOW",
+		2: "RISK_MEDIUM",
+		3: "RISK_HIGH",
+		4: "RISK_CRITICAL",
+	}
+	Risk_value = map[string]int32{
+		"RISK_UNSPECIFIED": 0,
+		"RISK_LOW":         1,
+		"RISK_MEDIUM":      2,
+		"RISK_HIGH":        3,
+		"RISK_CRITICAL":    4,
+	}
+)
+
+func (x Risk) Enum() *Risk {
+	p := new(Risk)
+	*p = x
+	return p
+}
+
+func (x Risk) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Risk) Descriptor() protoreflect.EnumDescriptor {
+	return file_snipeit_v1_entitlement_proto_enumTypes[0].Descriptor()
+}
+
+func (Risk) Type() protoreflect.EnumType {
+	return &file_snipeit_v1_entitlement_proto_enumTypes[0]
+}
+
+func (x Risk) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Risk.Descriptor instead.
+func (Risk) EnumDescriptor() ([]byte, []int) {
+	return file_snipeit_v1_entitlement_proto_rawDescGZIP(), []int{0}
+}
+
+// EntitlementSensitivity annotates the entitlements of roles and permissions with their risk and sensitivity
+// tags, so access policies can tell a superuser apart from somebody viewing assets.
+type EntitlementSensitivity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Subject:
+	//	*EntitlementSensitivity_Permission
+	//	*EntitlementSensitivity_Role
+	Subject isEntitlementSensitivity_Subject `protobuf_oneof:"subject"`
+	Risk    Risk                             `protobuf:"varint,3,opt,name=risk,proto3,enum=snipeit.v1.Risk" json:"risk,omitempty"`
+	// Sensitivity tags, e.g. superuser, admin, user_management, encrypted_custom_fields, api_access or imports.
+	Sensitivity []string `protobuf:"bytes,4,rep,name=sensitivity,proto3" json:"sensitivity,omitempty"`
+}
+
+func (x *EntitlementSensitivity) Reset() {
+	*x = EntitlementSensitivity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_snipeit_v1_entitlement_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EntitlementSensitivity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntitlementSensitivity) ProtoMessage() {}
+
+func (x *EntitlementSensitivity) ProtoReflect() protoreflect.Message {
+	mi := &file_snipeit_v1_entitlement_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntitlementSensitivity.ProtoReflect.Descriptor instead.
+func (*EntitlementSensitivity) Descriptor() ([]byte, []int) {
+	return file_snipeit_v1_entitlement_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *EntitlementSensitivity) GetSubject() isEntitlementSensitivity_Subject {
+	if m != nil {
+		return m.Subject
+	}
+	return nil
+}
+
+func (x *EntitlementSensitivity) GetPermission() string {
+	if x, ok := x.GetSubject().(*EntitlementSensitivity_Permission); ok {
+		return x.Permission
+	}
+	return ""
+}
+
+func (x *EntitlementSensitivity) GetRole() string {
+	if x, ok := x.GetSubject().(*EntitlementSensitivity_Role); ok {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *EntitlementSensitivity) GetRisk() Risk {
+	if x != nil {
+		return x.Risk
+	}
+	return Risk_RISK_UNSPECIFIED
+}
+
+func (x *EntitlementSensitivity) GetSensitivity() []string {
+	if x != nil {
+		return x.Sensitivity
+	}
+	return nil
+}
+
+type isEntitlementSensitivity_Subject interface {
+	isEntitlementSensitivity_Subject()
+}
+
+type EntitlementSensitivity_Permission struct {
+	// The Snipe-IT permission key, e.g. assets.view.encrypted_custom_fields.
+	Permission string `protobuf:"bytes,1,opt,name=permission,proto3,oneof"`
+}
+
+type EntitlementSensitivity_Role struct {
+	// The name of the role.
+	Role string `protobuf:"bytes,2,opt,name=role,proto3,oneof"`
+}
+
+func (*EntitlementSensitivity_Permission) isEntitlementSensitivity_Subject() {}
+
+func (*EntitlementSensitivity_Role) isEntitlementSensitivity_Subject() {}
+
+var File_snipeit_v1_entitlement_proto protoreflect.FileDescriptor
+
+var file_snipeit_v1_entitlement_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x73, 0x6e, 0x69, 0x70, 0x65, 0x69, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a,
+	0x73, 0x6e, 0x69, 0x70, 0x65, 0x69, 0x74, 0x2e, 0x76, 0x31, 0x22, 0xa3, 0x01, 0x0a, 0x16, 0x45,
+	0x6e, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x6e, 0x73, 0x69, 0x74,
+	0x69, 0x76, 0x69, 0x74, 0x79, 0x12, 0x20, 0x0a, 0x0a, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x70, 0x65, 0x72,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12, 0x24, 0x0a,
+	0x04, 0x72, 0x69, 0x73, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x73, 0x6e,
+	0x69, 0x70, 0x65, 0x69, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x69, 0x73, 0x6b, 0x52, 0x04, 0x72,
+	0x69, 0x73, 0x6b, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x69,
+	0x74, 0x79, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74,
+	0x69, 0x76, 0x69, 0x74, 0x79, 0x42, 0x09, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x2a, 0x5d, 0x0a, 0x04, 0x52, 0x69, 0x73, 0x6b, 0x12, 0x14, 0x0a, 0x10, 0x52, 0x49, 0x53, 0x4b,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0c,
+	0x0a, 0x08, 0x52, 0x49, 0x53, 0x4b, 0x5f, 0x4c, 0x4f, 0x57, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b,
+	0x52, 0x49, 0x53, 0x4b, 0x5f, 0x4d, 0x45, 0x44, 0x49, 0x55, 0x4d, 0x10, 0x02, 0x12, 0x0d, 0x0a,
+	0x09, 0x52, 0x49, 0x53, 0x4b, 0x5f, 0x48, 0x49, 0x47, 0x48, 0x10, 0x03, 0x12, 0x11, 0x0a, 0x0d,
+	0x52, 0x49, 0x53, 0x4b, 0x5f, 0x43, 0x52, 0x49, 0x54, 0x49, 0x43, 0x41, 0x4c, 0x10, 0x04, 0x42,
+	0x36, 0x5a, 0x34, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6f,
+	0x6e, 0x64, 0x75, 0x63, 0x74, 0x6f, 0x72, 0x6f, 0x6e, 0x65, 0x2f, 0x62, 0x61, 0x74, 0x6f, 0x6e,
+	0x2d, 0x73, 0x6e, 0x69, 0x70, 0x65, 0x2d, 0x69, 0x74, 0x2f, 0x70, 0x62, 0x2f, 0x73, 0x6e, 0x69,
+	0x70, 0x65, 0x69, 0x74, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_snipeit_v1_entitlement_proto_rawDescOnce sync.Once
+	file_snipeit_v1_entitlement_proto_rawDescData = file_snipeit_v1_entitlement_proto_rawDesc
+)
+
+func file_snipeit_v1_entitlement_proto_rawDescGZIP() []byte {
+	file_snipeit_v1_entitlement_proto_rawDescOnce.Do(func() {
+		file_snipeit_v1_entitlement_proto_rawDescData = protoimpl.X.CompressGZIP(file_snipeit_v1_entitlement_proto_rawDescData)
+	})
+	return file_snipeit_v1_entitlement_proto_rawDescData
+}
+
+var file_snipeit_v1_entitlement_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_snipeit_v1_entitlement_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_snipeit_v1_entitlement_proto_goTypes = []interface{}{
+	(Risk)(0),                      // 0: snipeit.v1.Risk
+	(*EntitlementSensitivity)(nil), // 1: snipeit.v1.EntitlementSensitivity
+}
+var file_snipeit_v1_entitlement_proto_depIdxs = []int32{
+	0, // 0: snipeit.v1.EntitlementSensitivity.risk:type_name -> snipeit.v1.Risk
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_snipeit_v1_entitlement_proto_init() }
+func file_snipeit_v1_entitlement_proto_init() {
+	if File_snipeit_v1_entitlement_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_snipeit_v1_entitlement_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EntitlementSensitivity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_snipeit_v1_entitlement_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*EntitlementSensitivity_Permission)(nil),
+		(*EntitlementSensitivity_Role)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_snipeit_v1_entitlement_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_snipeit_v1_entitlement_proto_goTypes,
+		DependencyIndexes: file_snipeit_v1_entitlement_proto_depIdxs,
+		EnumInfos:         file_snipeit_v1_entitlement_proto_enumTypes,
+		MessageInfos:      file_snipeit_v1_entitlement_proto_msgTypes,
+	}.Build()
+	File_snipeit_v1_entitlement_proto = out.File
+	file_snipeit_v1_entitlement_proto_rawDesc = nil
+	file_snipeit_v1_entitlement_proto_goTypes = nil
+	file_snipeit_v1_entitlement_proto_depIdxs = nil
+}
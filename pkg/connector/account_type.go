@@ -0,0 +1,82 @@
+package connector
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+
+	snipeit "github.com/conductorone/baton-snipe-it/pkg/snipe-it"
+)
+
+// apiTokenPermission lets a user create personal API tokens.
+const apiTokenPermission = "self.api"
+
+// Why a user was classified as a service account, recorded on its profile for reviewers.
+const (
+	serviceAccountNamingPattern      = "naming_pattern"
+	serviceAccountAPITokens          = "api_tokens"
+	serviceAccountNoInteractiveLogin = "no_interactive_login"
+)
+
+type (
+	// accountClassifier tells service accounts apart from the people using Snipe-IT.
+	accountClassifier struct {
+		pattern *regexp.Regexp
+		// noLoginAge is how long ago a user that never logged in must have been created to be a service account,
+		// zero leaves the last login out of the classification.
+		noLoginAge time.Duration
+		// groupAPITokens also counts users that may create API tokens through one of their groups.
+		groupAPITokens bool
+	}
+
+	accountClassification struct {
+		accountType v2.UserTrait_AccountType
+		reason      string
+	}
+)
+
+func newAccountClassifier(pattern string, noLoginAge time.Duration, groupAPITokens bool) (*accountClassifier, error) {
+	c := &accountClassifier{noLoginAge: noLoginAge, groupAPITokens: groupAPITokens}
+	if pattern == "" {
+		return c, nil
+	}
+
+	var err error
+	c.pattern, err = regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service account pattern %q: %w", pattern, err)
+	}
+
+	return c, nil
+}
+
+// classify returns whether the user is a service account: its username matches the naming pattern, it may
+// create API tokens, or, when enabled, it may log in but never did since it was created at least noLoginAge ago.
+// Snipe-IT only records logins to the web UI, so accounts used through the API alone never get a last login,
+// but neither do people who just joined or only sign in through SSO on some setups, hence the age and opt-in.
+// Only API tokens granted to the user itself count by default: groups often grant them to every member, and
+// superusers hold every permission without having asked for tokens.
+func (c *accountClassifier) classify(user *snipeit.User, resolver *snipeit.PermissionResolver) accountClassification {
+	if c != nil && c.pattern != nil && c.pattern.MatchString(user.Username) {
+		return accountClassification{accountType: v2.UserTrait_ACCOUNT_TYPE_SERVICE, reason: serviceAccountNamingPattern}
+	}
+
+	if user.Permissions[apiTokenPermission] == snipeit.Granted {
+		return accountClassification{accountType: v2.UserTrait_ACCOUNT_TYPE_SERVICE, reason: serviceAccountAPITokens}
+	}
+
+	if c != nil && c.groupAPITokens && resolver != nil {
+		if permission, ok := resolver.Resolve(user, apiTokenPermission); ok && permission.ImpliedBy == "" {
+			return accountClassification{accountType: v2.UserTrait_ACCOUNT_TYPE_SERVICE, reason: serviceAccountAPITokens}
+		}
+	}
+
+	if c != nil && c.noLoginAge > 0 && user.Activated && user.LastLogin.IsZero() &&
+		!user.CreatedAt.IsZero() && time.Since(user.CreatedAt.Time) >= c.noLoginAge {
+		return accountClassification{accountType: v2.UserTrait_ACCOUNT_TYPE_SERVICE, reason: serviceAccountNoInteractiveLogin}
+	}
+
+	return accountClassification{accountType: v2.UserTrait_ACCOUNT_TYPE_HUMAN}
+}
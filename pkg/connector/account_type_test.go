@@ -0,0 +1,117 @@
+package connector
+
+import (
+	"testing"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+
+	snipeit "github.com/conductorone/baton-snipe-it/pkg/snipe-it"
+)
+
+func TestAccountClassifierClassify(t *testing.T) {
+	resolver := snipeit.NewPermissionResolver([]snipeit.Group{
+		{ID: 1, Name: "Staff", Permissions: snipeit.Permissions{apiTokenPermission: snipeit.Granted}},
+		{ID: 2, Name: "Superusers", Permissions: snipeit.Permissions{snipeit.PermissionSuperuser: snipeit.Granted}},
+	})
+
+	member := func(permissions snipeit.Permissions, groupIDs ...int) *snipeit.User {
+		user := &snipeit.User{ID: 10, Username: "jdoe", Activated: true, Permissions: permissions}
+		for _, id := range groupIDs {
+			user.Groups.Rows = append(user.Groups.Rows, snipeit.Group{ID: id})
+		}
+		user.Groups.Total = len(user.Groups.Rows)
+
+		return user
+	}
+
+	service := func(reason string) accountClassification {
+		return accountClassification{accountType: v2.UserTrait_ACCOUNT_TYPE_SERVICE, reason: reason}
+	}
+	human := accountClassification{accountType: v2.UserTrait_ACCOUNT_TYPE_HUMAN}
+
+	tests := []struct {
+		name           string
+		pattern        string
+		groupAPITokens bool
+		user           *snipeit.User
+		want           accountClassification
+	}{
+		{
+			name: "no api tokens",
+			user: member(nil),
+			want: human,
+		},
+		{
+			name:    "naming pattern",
+			pattern: "^svc-",
+			user:    &snipeit.User{Username: "svc-backup"},
+			want:    service(serviceAccountNamingPattern),
+		},
+		{
+			name: "api tokens granted to the user",
+			user: member(snipeit.Permissions{apiTokenPermission: snipeit.Granted}),
+			want: service(serviceAccountAPITokens),
+		},
+		{
+			name: "api tokens granted through a group",
+			user: member(nil, 1),
+			want: human,
+		},
+		{
+			name:           "api tokens granted through a group when opted in",
+			groupAPITokens: true,
+			user:           member(nil, 1),
+			want:           service(serviceAccountAPITokens),
+		},
+		{
+			name:           "user denial beats the group grant",
+			groupAPITokens: true,
+			user:           member(snipeit.Permissions{apiTokenPermission: snipeit.Denied}, 1),
+			want:           human,
+		},
+		{
+			name: "superuser",
+			user: member(snipeit.Permissions{snipeit.PermissionSuperuser: snipeit.Granted}),
+			want: human,
+		},
+		{
+			name:           "superuser through a group when opted in",
+			groupAPITokens: true,
+			user:           member(nil, 2),
+			want:           human,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newAccountClassifier(tt.pattern, 0, tt.groupAPITokens)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := c.classify(tt.user, resolver); got != tt.want {
+				t.Errorf("classify() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccountClassifierNoLoginAge(t *testing.T) {
+	c, err := newAccountClassifier("", 30*24*time.Hour, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := &snipeit.User{Activated: true}
+	old.CreatedAt.Time = time.Now().Add(-60 * 24 * time.Hour)
+	recent := &snipeit.User{Activated: true}
+	recent.CreatedAt.Time = time.Now().Add(-24 * time.Hour)
+
+	if got := c.classify(old, nil); got != (accountClassification{accountType: v2.UserTrait_ACCOUNT_TYPE_SERVICE, reason: serviceAccountNoInteractiveLogin}) {
+		t.Errorf("classify() of a user that never logged in since 60 days = %+v, want a service account", got)
+	}
+	if got := c.classify(recent, nil); got.accountType != v2.UserTrait_ACCOUNT_TYPE_HUMAN {
+		t.Errorf("classify() of a user created yesterday = %+v, want a human", got)
+	}
+}
@@ -0,0 +1,143 @@
+package connector
+
+import (
+	"context"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	snipeit "github.com/conductorone/baton-snipe-it/pkg/snipe-it"
+)
+
+// defaultSiteName is used when the branding settings can't be read.
+const defaultSiteName = "Snipe-IT"
+
+var resourceTypeApp = &v2.ResourceType{
+	Id:          "app",
+	DisplayName: "Application",
+	Description: "The Snipe-IT instance",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_APP},
+	Annotations: getResourceTypeAnnotation(),
+}
+
+// appChildResourceTypes are listed under the app, every other resource belongs to it.
+var appChildResourceTypes = []*v2.ResourceType{
+	resourceTypeUser,
+	resourceTypeGroup,
+	resourceTypeRole,
+	resourceTypePermissionArea,
+	resourceTypeAsset,
+}
+
+type appResourceType struct {
+	resourceType *v2.ResourceType
+	client       *snipeit.Client
+	users        *userCache
+	history      *grantHistory
+}
+
+func (a *appResourceType) ResourceType(ctx context.Context) *v2.ResourceType {
+	return a.resourceType
+}
+
+// appResource is named after the site and identified by the normalized base URL, so instances installed under
+// different subpaths of the same host stay apart.
+func appResource(ctx context.Context, baseUrl string, version *snipeit.Version, settings *snipeit.Settings, logo snipeit.ImageURL) (*v2.Resource, error) {
+	name := defaultSiteName
+	if settings != nil && settings.SiteName != "" {
+		name = settings.SiteName
+	}
+
+	profile := map[string]interface{}{
+		"name": name,
+		"url":  baseUrl,
+	}
+	if version != nil {
+		profile["version"] = version.Version
+		profile["build_version"] = version.BuildVersion
+	}
+
+	appTraitOptions := []rs.AppTraitOption{
+		rs.WithAppProfile(profile),
+		rs.WithAppHelpURL(baseUrl),
+	}
+	if logo.IsUpload() {
+		appTraitOptions = append(appTraitOptions, rs.WithAppLogo(&v2.AssetRef{Id: string(logo)}))
+	}
+
+	var children []proto.Message
+	for _, resourceType := range appChildResourceTypes {
+		children = append(children, &v2.ChildResourceType{ResourceTypeId: resourceType.Id})
+	}
+
+	resource, err := rs.NewAppResource(name, resourceTypeApp, baseUrl, appTraitOptions, rs.WithAnnotation(children...))
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+func (a *appResourceType) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+	annos := annotations.Annotations{}
+
+	// The app is the only resource listed without a parent that calls the API, and every other resource is listed
+	// under it, so this is where a sync starts. Whatever a previous sync cached is dropped, and the version is
+	// detected again in case the instance was upgraded.
+	a.users.Reset(ctx)
+	a.history.Reset()
+
+	version, rldata, err := a.client.DetectVersion(ctx)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	if err != nil {
+		return nil, "", annos, wrapError(err, "Failed to get version")
+	}
+
+	settings, rldata, err := a.client.GetSettings(ctx)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	// The branding is cosmetic, the app is named Snipe-IT without a logo when it can't be read.
+	switch {
+	case status.Code(err) == codes.PermissionDenied || status.Code(err) == codes.NotFound:
+		l.Debug("baton-snipe-it: the branding settings are not available, using defaults", zap.Error(err))
+		settings = nil
+	case err != nil:
+		l.Warn("baton-snipe-it: failed to get branding settings, using defaults", zap.Error(err))
+		settings = nil
+	}
+
+	resource, err := appResource(ctx, a.client.BaseURL(), version, settings, a.client.LogoURL(settings))
+	if err != nil {
+		return nil, "", annos, wrapError(err, "Failed to get app resource")
+	}
+
+	return []*v2.Resource{resource}, "", annos, nil
+}
+
+func (a *appResourceType) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func (a *appResourceType) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newAppBuilder(client *snipeit.Client, users *userCache, history *grantHistory) *appResourceType {
+	return &appResourceType{
+		resourceType: resourceTypeApp,
+		client:       client,
+		users:        users,
+		history:      history,
+	}
+}
@@ -0,0 +1,196 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	snipeit "github.com/conductorone/baton-snipe-it/pkg/snipe-it"
+)
+
+// resourceTypeAsset is a hardware asset. Assets are modeled as groups whose members are the users they are
+// checked out to, so checkouts show up as grants.
+var resourceTypeAsset = &v2.ResourceType{
+	Id:          "asset",
+	DisplayName: "Asset",
+	Description: "A hardware asset in Snipe-IT",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
+}
+
+type assetResourceType struct {
+	resourceType *v2.ResourceType
+	client       *snipeit.Client
+	customFields *customFieldMapping
+}
+
+func (a *assetResourceType) ResourceType(ctx context.Context) *v2.ResourceType {
+	return a.resourceType
+}
+
+func assetResource(ctx context.Context, asset *snipeit.Hardware, customFields map[string]string, opts ...rs.ResourceOption) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"asset_id":    asset.ID,
+		"asset_tag":   asset.AssetTag,
+		"serial":      asset.Serial,
+		"requestable": strconv.FormatBool(asset.Requestable),
+	}
+
+	for key, reference := range map[string]*snipeit.Reference{
+		"model":        asset.Model,
+		"category":     asset.Category,
+		"manufacturer": asset.Manufacturer,
+		"company":      asset.Company,
+		"location":     asset.Location,
+	} {
+		if reference != nil {
+			profile[key] = reference.Name
+		}
+	}
+
+	if asset.StatusLabel != nil {
+		profile["status"] = asset.StatusLabel.Name
+	}
+
+	if asset.AssignedTo != nil && asset.AssignedTo.Type == snipeit.AssigneeTypeUser {
+		profile["assigned_to_user_id"] = asset.AssignedTo.ID
+	}
+
+	withCustomFields(profile, customFields)
+
+	groupTraitOptions := []rs.GroupTraitOption{
+		rs.WithGroupProfile(profile),
+	}
+
+	// The image is the asset's own picture, or its model's.
+	if asset.Image.IsUpload() {
+		groupTraitOptions = append(groupTraitOptions, rs.WithGroupIcon(&v2.AssetRef{Id: string(asset.Image)}))
+	}
+
+	resource, err := rs.NewGroupResource(assetDisplayName(asset), resourceTypeAsset, asset.ID, groupTraitOptions, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+// assetDisplayName falls back to the asset tag, assets don't need a name.
+func assetDisplayName(asset *snipeit.Hardware) string {
+	if asset.Name == "" {
+		return asset.AssetTag
+	}
+
+	return fmt.Sprintf("%s (%s)", asset.Name, asset.AssetTag)
+}
+
+func (a *assetResourceType) List(ctx context.Context, parentResourceID *v2.ResourceId, pt *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	// Assets are listed under the app.
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	annos := annotations.Annotations{}
+	bag, offset, err := parsePageToken(pt.Token, &v2.ResourceId{ResourceType: a.resourceType.Id})
+	if err != nil {
+		return nil, "", annos, err
+	}
+
+	// The field definitions may have changed since the previous sync.
+	if pt.Token == "" {
+		a.customFields.Reset()
+	}
+
+	assets, rldata, err := a.client.GetHardware(ctx, offset, resourcePageSize)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	// Asset checkout is optional, a token without assets.view syncs everything else.
+	if status.Code(err) == codes.PermissionDenied && offset == 0 {
+		ctxzap.Extract(ctx).Warn("baton-snipe-it: the API token may not view assets, skipping them", zap.Error(err))
+		return nil, "", annos, nil
+	}
+	if err != nil {
+		return nil, "", annos, wrapError(err, "Failed to get assets")
+	}
+
+	var resources []*v2.Resource
+	for _, asset := range assets.Rows {
+		asset := asset
+		customFields, rldata := a.customFields.profile(ctx, asset.CustomFields)
+		if rldata != nil {
+			annos.Append(rldata)
+		}
+
+		resource, err := assetResource(ctx, &asset, customFields, rs.WithParentResourceID(parentResourceID))
+		if err != nil {
+			return nil, "", annos, err
+		}
+
+		resources = append(resources, resource)
+	}
+
+	if isLastPage(len(assets.Rows), resourcePageSize) {
+		return resources, "", annos, nil
+	}
+
+	nextPage, err := handleNextPage(bag, offset+resourcePageSize)
+	if err != nil {
+		return nil, "", annos, err
+	}
+
+	return resources, nextPage, annos, nil
+}
+
+func (a *assetResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	assigmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeUser),
+		ent.WithDescription(fmt.Sprintf("Checked out %s", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s %s", resource.DisplayName, assignedEntitlement)),
+	}
+
+	entitlement := ent.NewAssignmentEntitlement(resource, assignedEntitlement, assigmentOptions...)
+	rv = append(rv, entitlement)
+
+	return rv, "", nil, nil
+}
+
+// Grants reads who the asset is checked out to from its profile, so it needs no further requests.
+func (a *assetResourceType) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	groupTrait, err := rs.GetGroupTrait(resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	userID, ok := rs.GetProfileInt64Value(groupTrait.Profile, "assigned_to_user_id")
+	if !ok {
+		return nil, "", nil, nil
+	}
+
+	principalID, err := rs.NewResourceID(resourceTypeUser, userID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return []*v2.Grant{grant.NewGrant(resource, assignedEntitlement, principalID)}, "", nil, nil
+}
+
+func newAssetBuilder(client *snipeit.Client, customFields *customFieldMapping) *assetResourceType {
+	return &assetResourceType{
+		resourceType: resourceTypeAsset,
+		client:       client,
+		customFields: customFields,
+	}
+}
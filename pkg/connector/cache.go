@@ -0,0 +1,419 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	snipeit "github.com/conductorone/baton-snipe-it/pkg/snipe-it"
+)
+
+// maxCachedPagesInMemory is how many user pages are kept in memory before further pages are spilled to disk.
+const maxCachedPagesInMemory = 200
+
+type (
+	// userCache serves repeated user list requests made during a sync from memory. Users, roles and groups
+	// all walk the full user list, so without it a sync downloads the user table several times over.
+	// It holds on to the group list as well, which permission resolution needs for every page of users, and
+	// to the set of granted permission keys.
+	userCache struct {
+		client *snipeit.Client
+		// stateFile is where an incremental user sync keeps the users between syncs, empty when it is disabled.
+		stateFile string
+
+		mu          sync.Mutex
+		pages       map[string]*cachedPage
+		prefetched  map[string]bool
+		inMemory    int
+		spill       *os.File
+		spillPath   string
+		spillSize   int64
+		groupIndex  map[int][]int
+		groups      []snipeit.Group
+		grantedKeys []string
+		primed      bool
+
+		primeMu sync.Mutex
+		fetchMu sync.Mutex
+		indexMu sync.Mutex
+	}
+
+	// cachedPage is a page held in memory, or the location of a page spilled to disk.
+	cachedPage struct {
+		users  *snipeit.UsersResponse
+		offset int64
+		size   int
+	}
+)
+
+func newUserCache(client *snipeit.Client, stateFile string) *userCache {
+	return &userCache{
+		client:     client,
+		stateFile:  stateFile,
+		pages:      make(map[string]*cachedPage),
+		prefetched: make(map[string]bool),
+	}
+}
+
+// GetUsers returns the requested page from the cache. The first miss for a query prefetches every following
+// page in parallel, so the connector can keep handing out one deterministic page at a time. With an incremental
+// user sync, the unfiltered list is primed from the previous sync before it is first fetched.
+func (c *userCache) GetUsers(ctx context.Context, offset, limit int, query ...snipeit.QueryFunction) (*snipeit.UsersResponse, *v2.RateLimitDescription, error) {
+	queryKey := snipeit.EncodeQuery(query...)
+	key := pageKey(queryKey, offset, limit)
+
+	var primeRldata *v2.RateLimitDescription
+	if c.stateFile != "" && len(query) == 0 {
+		var err error
+		primeRldata, err = c.primeFromPreviousSync(ctx)
+		if err != nil {
+			return nil, primeRldata, fmt.Errorf("failed to load users changed since the previous sync: %w", err)
+		}
+	}
+
+	users, err := c.lookup(key)
+	if err != nil || users != nil {
+		return users, primeRldata, err
+	}
+
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+
+	// Another caller may have fetched the page while we were waiting.
+	users, err = c.lookup(key)
+	if err != nil || users != nil {
+		return users, nil, err
+	}
+
+	prefetchKey := fmt.Sprintf("%s|%d", queryKey, limit)
+	if !c.isPrefetched(prefetchKey) {
+		rldata, err := c.client.ForEachUsersPage(ctx, offset, limit, func(pageOffset int, users *snipeit.UsersResponse) error {
+			return c.store(pageKey(queryKey, pageOffset, limit), users)
+		}, query...)
+		if err != nil {
+			return nil, rldata, err
+		}
+
+		c.mu.Lock()
+		c.prefetched[prefetchKey] = true
+		c.mu.Unlock()
+
+		users, err = c.lookup(key)
+		return users, rldata, err
+	}
+
+	users, rldata, err := c.client.GetUsers(ctx, offset, limit, query...)
+	if err != nil {
+		return nil, rldata, err
+	}
+
+	err = c.store(key, users)
+	if err != nil {
+		return nil, rldata, err
+	}
+
+	return users, rldata, nil
+}
+
+// Prime serves the unfiltered user list from users, as if it had been fetched page by page.
+func (c *userCache) Prime(users []snipeit.User, limit int) error {
+	queryKey := snipeit.EncodeQuery()
+
+	for offset := 0; ; offset += limit {
+		end := min(offset+limit, len(users))
+		page := &snipeit.UsersResponse{
+			Total: int64(len(users)),
+			Rows:  users[offset:end],
+		}
+
+		err := c.store(pageKey(queryKey, offset, limit), page)
+		if err != nil {
+			return err
+		}
+
+		if isLastPage(len(page.Rows), limit) {
+			break
+		}
+	}
+
+	c.mu.Lock()
+	c.prefetched[fmt.Sprintf("%s|%d", queryKey, limit)] = true
+	c.mu.Unlock()
+
+	return nil
+}
+
+func pageKey(queryKey string, offset, limit int) string {
+	return fmt.Sprintf("%s|%d|%d", queryKey, offset, limit)
+}
+
+// lookup returns the cached page, or nil if it has not been fetched yet.
+func (c *userCache) lookup(key string) (*snipeit.UsersResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	page, ok := c.pages[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return page.load(c.spill)
+}
+
+func (c *userCache) isPrefetched(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.prefetched[key]
+}
+
+// Reset drops everything cached so far. It is called when a new sync lists the app and after provisioning
+// changes group memberships.
+func (c *userCache) Reset(ctx context.Context) {
+	l := ctxzap.Extract(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.spill != nil {
+		err := c.spill.Close()
+		if err != nil {
+			l.Warn("baton-snipe-it: failed to close user cache file", zap.Error(err))
+		}
+	}
+
+	if c.spillPath != "" {
+		err := os.Remove(c.spillPath)
+		if err != nil {
+			l.Warn("baton-snipe-it: failed to remove user cache file", zap.String("path", c.spillPath), zap.Error(err))
+		}
+	}
+
+	c.pages = make(map[string]*cachedPage)
+	c.prefetched = make(map[string]bool)
+	c.inMemory = 0
+	c.spill = nil
+	c.spillPath = ""
+	c.spillSize = 0
+	c.groupIndex = nil
+	c.groups = nil
+	c.grantedKeys = nil
+	c.primed = false
+}
+
+// Groups returns every group along with its permissions, fetched once per sync.
+func (c *userCache) Groups(ctx context.Context) ([]snipeit.Group, *v2.RateLimitDescription, error) {
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+
+	c.mu.Lock()
+	groups := c.groups
+	c.mu.Unlock()
+
+	if groups != nil {
+		return groups, nil, nil
+	}
+
+	res, rldata, err := c.client.GetAllGroups(ctx)
+	if err != nil {
+		return nil, rldata, err
+	}
+
+	// Keep an empty, non-nil slice so an instance without groups isn't asked again.
+	groups = append([]snipeit.Group{}, res.Rows...)
+
+	c.mu.Lock()
+	c.groups = groups
+	c.mu.Unlock()
+
+	return groups, rldata, nil
+}
+
+// GroupMembers returns the IDs of the users in the group. Memberships of every group are indexed in a single
+// pass over the user list, relying on each user carrying its groups, so later groups are served from memory.
+func (c *userCache) GroupMembers(ctx context.Context, groupID int) ([]int, *v2.RateLimitDescription, error) {
+	c.indexMu.Lock()
+	defer c.indexMu.Unlock()
+
+	c.mu.Lock()
+	index := c.groupIndex
+	c.mu.Unlock()
+
+	var rldata *v2.RateLimitDescription
+	if index == nil {
+		index = make(map[int][]int)
+		for offset := 0; ; offset += resourcePageSize {
+			users, pageRldata, err := c.GetUsers(ctx, offset, resourcePageSize)
+			if pageRldata != nil {
+				rldata = pageRldata
+			}
+			if err != nil {
+				return nil, rldata, err
+			}
+
+			for _, user := range users.Rows {
+				for _, group := range user.Groups.Rows {
+					index[group.ID] = append(index[group.ID], user.ID)
+				}
+			}
+
+			if isLastPage(len(users.Rows), resourcePageSize) {
+				break
+			}
+		}
+
+		c.mu.Lock()
+		c.groupIndex = index
+		c.mu.Unlock()
+	}
+
+	return index[groupID], rldata, nil
+}
+
+// GrantedPermissions returns every permission key granted to at least one group or user, sorted. Like
+// GroupMembers, it is computed in a single pass over the user list and then served from memory.
+func (c *userCache) GrantedPermissions(ctx context.Context) ([]string, *v2.RateLimitDescription, error) {
+	c.indexMu.Lock()
+	defer c.indexMu.Unlock()
+
+	c.mu.Lock()
+	keys := c.grantedKeys
+	c.mu.Unlock()
+
+	if keys != nil {
+		return keys, nil, nil
+	}
+
+	groups, rldata, err := c.Groups(ctx)
+	if err != nil {
+		return nil, rldata, err
+	}
+
+	keys = []string{}
+	seen := make(map[string]bool)
+	add := func(permissions snipeit.Permissions) {
+		for key, value := range permissions {
+			if value == snipeit.Granted && !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	for _, group := range groups {
+		add(group.Permissions)
+	}
+
+	for offset := 0; ; offset += resourcePageSize {
+		users, pageRldata, err := c.GetUsers(ctx, offset, resourcePageSize)
+		if pageRldata != nil {
+			rldata = pageRldata
+		}
+		if err != nil {
+			return nil, rldata, err
+		}
+
+		for _, user := range users.Rows {
+			add(user.Permissions)
+		}
+
+		if isLastPage(len(users.Rows), resourcePageSize) {
+			break
+		}
+	}
+
+	sort.Strings(keys)
+
+	c.mu.Lock()
+	c.grantedKeys = keys
+	c.mu.Unlock()
+
+	return keys, rldata, nil
+}
+
+func (c *userCache) store(key string, users *snipeit.UsersResponse) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.inMemory < maxCachedPagesInMemory {
+		c.pages[key] = &cachedPage{users: users}
+		c.inMemory++
+
+		return nil
+	}
+
+	if c.spill == nil {
+		spill, spillPath, err := newSpillFile()
+		if err != nil {
+			return err
+		}
+		c.spill, c.spillPath = spill, spillPath
+	}
+
+	data, err := json.Marshal(users)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.spill.WriteAt(data, c.spillSize)
+	if err != nil {
+		return err
+	}
+
+	c.pages[key] = &cachedPage{offset: c.spillSize, size: len(data)}
+	c.spillSize += int64(len(data))
+
+	return nil
+}
+
+// newSpillFile creates the file user pages are spilled to, readable by the connector's user only. It is removed
+// right away and only reachable through the returned handle, so the user records it holds are gone once the
+// connector exits, however it exits. Where an open file can't be removed its path is returned, to be removed
+// when the cache is reset.
+func newSpillFile() (*os.File, string, error) {
+	f, err := os.CreateTemp("", "baton-snipe-it-users-*.json")
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = f.Chmod(stateFileMode)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return nil, "", err
+	}
+
+	err = os.Remove(f.Name())
+	if err != nil {
+		return f, f.Name(), nil
+	}
+
+	return f, "", nil
+}
+
+func (p *cachedPage) load(spill *os.File) (*snipeit.UsersResponse, error) {
+	if p.users != nil {
+		return p.users, nil
+	}
+
+	data := make([]byte, p.size)
+	_, err := spill.ReadAt(data, p.offset)
+	if err != nil {
+		return nil, err
+	}
+
+	users := new(snipeit.UsersResponse)
+	err = json.Unmarshal(data, users)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
@@ -2,8 +2,9 @@ package connector
 
 import (
 	"context"
+	"fmt"
 	"io"
-	"net/url"
+	"time"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
@@ -15,55 +16,135 @@ import (
 
 type SnipeIt struct {
 	client *snipeit.Client
+	users  *userCache
+
+	clientOptions       []snipeit.Option
+	timezone            string
+	membershipFromUsers bool
+	userStateFile       string
+
+	effectivePermissions  bool
+	permissionsConfigFile string
+	permissions           *permissionsConfig
+
+	grantHistory         bool
+	grantHistoryLookback time.Duration
+	history              *grantHistory
+
+	serviceAccountPattern  string
+	serviceAccountNoLogin  time.Duration
+	serviceAccountGroupAPI bool
+	accounts               *accountClassifier
+
+	customFieldMappings        []string
+	allowEncryptedCustomFields bool
+	customFields               *customFieldMapping
 }
 
 // ResourceSyncers returns a ResourceSyncer for each resource type that should be synced from the upstream service.
 func (d *SnipeIt) ResourceSyncers(ctx context.Context) []connectorbuilder.ResourceSyncer {
 	return []connectorbuilder.ResourceSyncer{
-		newUserBuilder(d.client),
-		newGroupBuilder(d.client),
-		newRoleBuilder(d.client),
+		newAppBuilder(d.client, d.users, d.history),
+		newUserBuilder(d.client, d.users, d.accounts),
+		newGroupBuilder(d.client, d.users, d.membershipFromUsers, d.history),
+		newRoleBuilder(d.client, d.users, d.effectivePermissions, d.permissions, d.history),
+		newPermissionAreaBuilder(d.client, d.users, d.effectivePermissions, d.permissions, d.history),
+		newAssetBuilder(d.client, d.customFields),
 	}
 }
 
 // Asset takes an input AssetRef and attempts to fetch it using the connector's authenticated http client
 // It streams a response, always starting with a metadata object, following by chunked payloads for the asset.
+// Asset references are the URLs of images uploaded to Snipe-IT: user avatars, asset and model pictures and the
+// site logo. Companies aren't synced as resources, so their logos are never referenced.
 func (d *SnipeIt) Asset(ctx context.Context, asset *v2.AssetRef) (string, io.ReadCloser, error) {
-	return "", nil, nil
+	contentType, body, _, err := d.client.GetImage(ctx, asset.Id)
+	if err != nil {
+		return "", nil, wrapError(err, "Failed to get asset")
+	}
+
+	return contentType, body, nil
 }
 
 // Metadata returns metadata about the connector.
 func (d *SnipeIt) Metadata(ctx context.Context) (*v2.ConnectorMetadata, error) {
 	return &v2.ConnectorMetadata{
 		DisplayName: "Snipe-IT",
-		Description: "Connector syncing Snipe-IT users and their groups to Baton.",
+		Description: "Connector syncing Snipe-IT users, groups, roles, permissions and assets to Baton.",
 	}, nil
 }
 
 // Validate is called to ensure that the connector is properly configured. It should exercise any API credentials
 // to be sure that they are valid.
 func (d *SnipeIt) Validate(ctx context.Context) (annotations.Annotations, error) {
-	err := d.client.Validate(ctx)
+	owner, err := d.client.Validate(ctx)
 	if err != nil {
-		return nil, wrapError(err, "Not enough permissions to get users")
+		return nil, wrapError(err, "Failed to get the owner of the API token")
 	}
 
-	return nil, nil
+	_, _, err = d.client.DetectVersion(ctx)
+	if err != nil {
+		return nil, wrapError(err, "Failed to detect the Snipe-IT version")
+	}
+
+	annos := annotations.Annotations{}
+	report, err := d.validatePermissions(ctx, owner)
+	annos.Update(report)
+	if err != nil {
+		return annos, err
+	}
+
+	return annos, nil
 }
 
 // New returns a new instance of the connector.
-func New(ctx context.Context, baseUrl string, accessToken string) (*SnipeIt, error) {
+func New(ctx context.Context, baseUrl string, accessToken string, opts ...Option) (*SnipeIt, error) {
 	httpClient, err := uhttp.NewBearerAuth(accessToken).GetClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := url.Parse(baseUrl)
+	d := &SnipeIt{}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	if d.permissionsConfigFile != "" {
+		d.permissions, err = loadPermissionsConfig(d.permissionsConfigFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	d.accounts, err = newAccountClassifier(d.serviceAccountPattern, d.serviceAccountNoLogin, d.serviceAccountGroupAPI)
 	if err != nil {
 		return nil, err
 	}
 
-	return &SnipeIt{
-		client: snipeit.New(u.String(), httpClient),
-	}, nil
+	customFieldKeys, err := parseCustomFieldMapping(d.customFieldMappings)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.timezone != "" {
+		loc, err := time.LoadLocation(d.timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", d.timezone, err)
+		}
+		d.clientOptions = append(d.clientOptions, snipeit.WithLocation(loc))
+	}
+
+	d.client = snipeit.New(baseUrl, httpClient, d.clientOptions...)
+	_, err = d.client.NormalizeBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.users = newUserCache(d.client, d.userStateFile)
+
+	d.customFields = newCustomFieldMapping(d.client, customFieldKeys, d.allowEncryptedCustomFields)
+	if d.grantHistory {
+		d.history = newGrantHistory(d.client, d.grantHistoryLookback)
+	}
+
+	return d, nil
 }
@@ -0,0 +1,147 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	snipeit "github.com/conductorone/baton-snipe-it/pkg/snipe-it"
+)
+
+// customFieldMapping copies the values of custom fields into the profiles of assets, the only items Snipe-IT
+// has custom fields on. Fields are matched by name or database column, e.g. "Cost Center" or
+// "_snipeit_cost_center_3".
+type customFieldMapping struct {
+	client *snipeit.Client
+	// keys maps a field to the profile key its value is stored under.
+	keys           map[string]string
+	allowEncrypted bool
+
+	mu sync.Mutex
+	// encrypted tells, by database column, whether each known field is encrypted, nil until loaded.
+	encrypted map[string]bool
+}
+
+// parseCustomFieldMapping parses field=profile_key pairs.
+func parseCustomFieldMapping(mappings []string) (map[string]string, error) {
+	keys := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		field, key, ok := strings.Cut(mapping, "=")
+		field, key = strings.TrimSpace(field), strings.TrimSpace(key)
+		if !ok || field == "" || key == "" {
+			return nil, fmt.Errorf("invalid custom field mapping %q, expected field=profile_key", mapping)
+		}
+
+		keys[field] = key
+	}
+
+	return keys, nil
+}
+
+func newCustomFieldMapping(client *snipeit.Client, keys map[string]string, allowEncrypted bool) *customFieldMapping {
+	return &customFieldMapping{
+		client:         client,
+		keys:           keys,
+		allowEncrypted: allowEncrypted,
+	}
+}
+
+// Reset forgets which fields are encrypted, so they are fetched again in the next sync.
+func (m *customFieldMapping) Reset() {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.encrypted = nil
+}
+
+// profile returns the mapped custom field values, keyed by profile key. Encrypted fields, and fields that can't be
+// told apart from them, are left out unless encrypted fields were explicitly allowed.
+func (m *customFieldMapping) profile(ctx context.Context, fields snipeit.CustomFields) (map[string]string, *v2.RateLimitDescription) {
+	if m == nil || len(m.keys) == 0 || len(fields) == 0 {
+		return nil, nil
+	}
+
+	encrypted, rldata := m.encryptedFields(ctx)
+
+	// Iterate in name order, so two fields mapped onto the same key resolve the same way every sync.
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rv := make(map[string]string)
+	for _, name := range names {
+		field := fields[name]
+
+		key, ok := m.keys[name]
+		if !ok {
+			key, ok = m.keys[field.DBColumn]
+		}
+		if !ok {
+			continue
+		}
+		if !m.allowEncrypted {
+			if isEncrypted, known := encrypted[field.DBColumn]; !known || isEncrypted {
+				continue
+			}
+		}
+
+		if _, ok := rv[key]; !ok {
+			rv[key] = field.Value
+		}
+	}
+
+	return rv, rldata
+}
+
+// encryptedFields loads which fields are encrypted once per sync. Listing the fields requires customfields.view,
+// without it no field is known and every mapped field is skipped, with a warning, instead of failing the sync.
+func (m *customFieldMapping) encryptedFields(ctx context.Context) (map[string]bool, *v2.RateLimitDescription) {
+	if m.allowEncrypted {
+		return nil, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.encrypted != nil {
+		return m.encrypted, nil
+	}
+
+	m.encrypted = make(map[string]bool)
+
+	fields, rldata, err := m.client.GetCustomFields(ctx)
+	if err != nil {
+		ctxzap.Extract(ctx).Warn(
+			"baton-snipe-it: failed to get custom fields, skipping custom fields that may be encrypted",
+			zap.Error(err),
+		)
+		return m.encrypted, rldata
+	}
+
+	for _, field := range fields {
+		m.encrypted[field.DBColumn] = field.Encrypted
+	}
+
+	return m.encrypted, rldata
+}
+
+// withCustomFields adds the custom field values to the profile, keys the connector already uses are kept.
+func withCustomFields(profile map[string]interface{}, customFields map[string]string) {
+	for key, value := range customFields {
+		if _, ok := profile[key]; !ok {
+			profile[key] = value
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	snipeit "github.com/conductorone/baton-snipe-it/pkg/snipe-it"
+)
+
+// eventCursor is the position in the activity log, which is read oldest first. The offset alone would skip
+// entries if older ones were deleted in the meantime, so the last ID seen is kept as well.
+type eventCursor struct {
+	Offset int `json:"offset"`
+	LastID int `json:"last_id"`
+}
+
+func parseEventCursor(cursor string) (*eventCursor, error) {
+	rv := &eventCursor{}
+	if cursor == "" {
+		return rv, nil
+	}
+
+	err := json.Unmarshal([]byte(cursor), rv)
+	if err != nil {
+		return nil, fmt.Errorf("baton-snipe-it: invalid event cursor: %w", err)
+	}
+
+	return rv, nil
+}
+
+func (c *eventCursor) String() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// ListEvents translates the activity log into events: asset checkouts and checkins, user creation, and changes
+// to the permissions and groups of users and groups.
+func (d *SnipeIt) ListEvents(
+	ctx context.Context,
+	earliestEvent *timestamppb.Timestamp,
+	pToken *pagination.StreamToken,
+) ([]*v2.Event, *pagination.StreamState, annotations.Annotations, error) {
+	annos := annotations.Annotations{}
+
+	cursor, err := parseEventCursor(pToken.Cursor)
+	if err != nil {
+		return nil, nil, annos, err
+	}
+
+	size := pToken.Size
+	if size <= 0 {
+		size = resourcePageSize
+	}
+
+	if pToken.Cursor == "" && earliestEvent != nil {
+		count, total, rldata, err := d.client.CountActivitySince(ctx, earliestEvent.AsTime(), size)
+		if rldata != nil {
+			annos.Append(rldata)
+		}
+		if err != nil {
+			return nil, nil, annos, wrapError(err, "Failed to find the earliest activity")
+		}
+
+		cursor.Offset = int(total - count)
+	}
+
+	activities, rldata, err := d.client.GetActivity(
+		ctx,
+		cursor.Offset,
+		size,
+		snipeit.WithSort("id"),
+		snipeit.WithOrder(snipeit.SortAscending),
+	)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	if err != nil {
+		return nil, nil, annos, wrapError(err, "Failed to get activity")
+	}
+
+	var rv []*v2.Event
+	for _, activity := range activities.Rows {
+		activity := activity
+		if activity.ID <= cursor.LastID {
+			continue
+		}
+		cursor.LastID = activity.ID
+
+		// Compare when the activity was logged, like the start offset does. Its action date can be backdated.
+		if earliestEvent != nil && activity.CreatedAt.Before(earliestEvent.AsTime()) {
+			continue
+		}
+
+		events, err := d.activityEvents(ctx, &activity)
+		if err != nil {
+			// One malformed entry must not stall the feed.
+			ctxzap.Extract(ctx).Warn(
+				"baton-snipe-it: skipping activity",
+				zap.Int("activity_id", activity.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		rv = append(rv, events...)
+	}
+	cursor.Offset += len(activities.Rows)
+
+	nextCursor, err := cursor.String()
+	if err != nil {
+		return nil, nil, annos, err
+	}
+
+	streamState := &pagination.StreamState{
+		Cursor:  nextCursor,
+		HasMore: !isLastPage(len(activities.Rows), size),
+	}
+
+	return rv, streamState, annos, nil
+}
+
+func (d *SnipeIt) activityEvents(ctx context.Context, activity *snipeit.Activity) ([]*v2.Event, error) {
+	if activity.Item == nil {
+		return nil, nil
+	}
+
+	switch activity.Item.Type {
+	case snipeit.ItemTypeAsset:
+		return d.checkoutEvents(ctx, activity)
+
+	case snipeit.ItemTypeUser:
+		switch activity.ActionType {
+		case snipeit.ActionCreate, snipeit.ActionCreateNew:
+			return usageEvent(activity, resourceTypeUser)
+		case snipeit.ActionUpdate:
+			principal, err := activityResource(activity.Item, resourceTypeUser)
+			if err != nil {
+				return nil, err
+			}
+
+			events, err := d.permissionEvents(ctx, activity, principal)
+			if err != nil {
+				return nil, err
+			}
+
+			groupEvents, err := groupMembershipEvents(activity, principal)
+			if err != nil {
+				return nil, err
+			}
+
+			return append(events, groupEvents...), nil
+		}
+
+	case snipeit.ItemTypeGroup:
+		switch activity.ActionType {
+		case snipeit.ActionCreate, snipeit.ActionCreateNew, snipeit.ActionDelete:
+			return usageEvent(activity, resourceTypeGroup)
+		case snipeit.ActionUpdate:
+			principal, err := activityResource(activity.Item, resourceTypeGroup)
+			if err != nil {
+				return nil, err
+			}
+
+			return d.permissionEvents(ctx, activity, principal)
+		}
+	}
+
+	return nil, nil
+}
+
+// checkoutEvents turns checking an asset out to a user into a grant, and checking it back in into a revoke.
+func (d *SnipeIt) checkoutEvents(ctx context.Context, activity *snipeit.Activity) ([]*v2.Event, error) {
+	if activity.Target == nil || activity.Target.Type != snipeit.ItemTypeUser {
+		return nil, nil
+	}
+
+	asset, err := activityResource(activity.Item, resourceTypeAsset)
+	if err != nil {
+		return nil, err
+	}
+
+	principal, err := activityResource(activity.Target, resourceTypeUser)
+	if err != nil {
+		return nil, err
+	}
+
+	switch activity.ActionType {
+	case snipeit.ActionCheckout:
+		return []*v2.Event{grantEvent(activity, "", grant.NewGrant(asset, assignedEntitlement, principal.Id))}, nil
+	case snipeit.ActionCheckin:
+		entitlement := ent.NewAssignmentEntitlement(asset, assignedEntitlement)
+		return []*v2.Event{revokeEvent(activity, "", entitlement, principal)}, nil
+	}
+
+	return nil, nil
+}
+
+// permissionEvents grants and revokes the entitlements of the permissions the activity changed on the principal.
+func (d *SnipeIt) permissionEvents(ctx context.Context, activity *snipeit.Activity, principal *v2.Resource) ([]*v2.Event, error) {
+	granted, revoked, err := activity.PermissionChanges()
+	if err != nil {
+		return nil, err
+	}
+
+	var rv []*v2.Event
+	for _, key := range granted {
+		entitlement, err := d.permissionEntitlement(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if entitlement == nil {
+			continue
+		}
+
+		rv = append(rv, grantEvent(activity, key, grant.NewGrant(entitlement.Resource, entitlement.Slug, principal.Id)))
+	}
+
+	for _, key := range revoked {
+		entitlement, err := d.permissionEntitlement(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if entitlement == nil {
+			continue
+		}
+
+		rv = append(rv, revokeEvent(activity, key, entitlement, principal))
+	}
+
+	return rv, nil
+}
+
+// permissionEntitlement returns the entitlement modeling the permission: the role for the key of an admin role,
+// otherwise the permission entitlement of its area. Nil means the key isn't modeled.
+func (d *SnipeIt) permissionEntitlement(ctx context.Context, key string) (*v2.Entitlement, error) {
+	if !d.permissions.isAdminPermission(key) {
+		area, err := permissionAreaResource(ctx, permissionArea(key))
+		if err != nil {
+			return nil, err
+		}
+
+		return ent.NewPermissionEntitlement(area, key), nil
+	}
+
+	for _, role := range d.permissions.roles() {
+		if !role.Admin || len(role.Permissions) != 1 || role.Permissions[0] != key {
+			continue
+		}
+
+		resource, err := roleResource(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+
+		return ent.NewAssignmentEntitlement(resource, assignedEntitlement), nil
+	}
+
+	return nil, nil
+}
+
+func groupMembershipEvents(activity *snipeit.Activity, principal *v2.Resource) ([]*v2.Event, error) {
+	added, removed, err := activity.GroupChanges()
+	if err != nil {
+		return nil, err
+	}
+
+	var rv []*v2.Event
+	for _, groupID := range added {
+		group, err := rs.NewResource(strconv.Itoa(groupID), resourceTypeGroup, groupID)
+		if err != nil {
+			return nil, err
+		}
+
+		rv = append(rv, grantEvent(activity, "group:"+group.Id.Resource, grant.NewGrant(group, memberEntitlement, principal.Id)))
+	}
+
+	for _, groupID := range removed {
+		group, err := rs.NewResource(strconv.Itoa(groupID), resourceTypeGroup, groupID)
+		if err != nil {
+			return nil, err
+		}
+
+		entitlement := ent.NewAssignmentEntitlement(group, memberEntitlement)
+		rv = append(rv, revokeEvent(activity, "group:"+group.Id.Resource, entitlement, principal))
+	}
+
+	return rv, nil
+}
+
+func usageEvent(activity *snipeit.Activity, resourceType *v2.ResourceType) ([]*v2.Event, error) {
+	target, err := activityResource(activity.Item, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &v2.UsageEvent{
+		TargetResource: target,
+	}
+
+	if actor := activity.Actor(); actor != nil {
+		usage.ActorResource, err = activityResource(actor, resourceTypeUser)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return []*v2.Event{{
+		Id:         eventID(activity, ""),
+		OccurredAt: timestamppb.New(activity.OccurredAt()),
+		Event:      &v2.Event_UsageEvent{UsageEvent: usage},
+	}}, nil
+}
+
+func grantEvent(activity *snipeit.Activity, suffix string, g *v2.Grant) *v2.Event {
+	return &v2.Event{
+		Id:         eventID(activity, suffix),
+		OccurredAt: timestamppb.New(activity.OccurredAt()),
+		Event: &v2.Event_GrantEvent{
+			GrantEvent: &v2.GrantEvent{Grant: g},
+		},
+	}
+}
+
+func revokeEvent(activity *snipeit.Activity, suffix string, entitlement *v2.Entitlement, principal *v2.Resource) *v2.Event {
+	return &v2.Event{
+		Id:         eventID(activity, suffix),
+		OccurredAt: timestamppb.New(activity.OccurredAt()),
+		Event: &v2.Event_RevokeEvent{
+			RevokeEvent: &v2.RevokeEvent{
+				Entitlement: entitlement,
+				Principal:   principal,
+			},
+		},
+	}
+}
+
+// eventID identifies the event by its activity, suffixed when a single activity produces several events.
+func eventID(activity *snipeit.Activity, suffix string) string {
+	if suffix == "" {
+		return strconv.Itoa(activity.ID)
+	}
+
+	return fmt.Sprintf("%d:%s", activity.ID, suffix)
+}
+
+// activityResource references a resource of the activity log. It only carries the ID and name, which is all
+// the log knows about it.
+func activityResource(item *snipeit.ActivityItem, resourceType *v2.ResourceType) (*v2.Resource, error) {
+	return rs.NewResource(item.Name, resourceType, item.ID)
+}
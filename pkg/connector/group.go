@@ -25,15 +25,18 @@ var resourceTypeGroup = &v2.ResourceType{
 }
 
 type groupResourceType struct {
-	resourceType *v2.ResourceType
-	client       *snipeit.Client
+	resourceType        *v2.ResourceType
+	client              *snipeit.Client
+	users               *userCache
+	membershipFromUsers bool
+	history             *grantHistory
 }
 
 func (o *groupResourceType) ResourceType(ctx context.Context) *v2.ResourceType {
 	return o.resourceType
 }
 
-func groupResource(ctx context.Context, group *snipeit.Group) (*v2.Resource, error) {
+func groupResource(ctx context.Context, group *snipeit.Group, opts ...rs.ResourceOption) (*v2.Resource, error) {
 	profile := map[string]interface{}{
 		"name":     group.Name,
 		"group_id": group.ID,
@@ -42,7 +45,7 @@ func groupResource(ctx context.Context, group *snipeit.Group) (*v2.Resource, err
 	groupTraitOptions := []rs.GroupTraitOption{
 		rs.WithGroupProfile(profile),
 	}
-	resource, err := rs.NewGroupResource(group.Name, resourceTypeGroup, group.ID, groupTraitOptions)
+	resource, err := rs.NewGroupResource(group.Name, resourceTypeGroup, group.ID, groupTraitOptions, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -77,7 +80,11 @@ func (g *groupResourceType) Grants(ctx context.Context, resource *v2.Resource, p
 		return nil, "", annos, err
 	}
 
-	users, rldata, err := g.client.GetUsers(ctx, offset, resourcePageSize, snipeit.WithGroupId(groupID))
+	if g.membershipFromUsers {
+		return g.grantsFromMembershipIndex(ctx, resource, groupID, bag, offset)
+	}
+
+	users, rldata, err := g.users.GetUsers(ctx, offset, resourcePageSize, snipeit.WithGroupId(groupID))
 	if rldata != nil {
 		annos.Append(rldata)
 	}
@@ -92,12 +99,20 @@ func (g *groupResourceType) Grants(ctx context.Context, resource *v2.Resource, p
 		}
 
 		user := user
-		userResource, err := userResource(ctx, &user)
+		userResource, err := userResource(ctx, &user, accountClassification{})
 		if err != nil {
 			return nil, "", annos, err
 		}
 
-		grant := grant.NewGrant(resource, memberEntitlement, userResource.Id)
+		change, rldata, err := g.history.Latest(ctx, userResource.Id, groupSubject(groupID))
+		if rldata != nil {
+			annos.Append(rldata)
+		}
+		if err != nil {
+			return nil, "", annos, wrapError(err, "Failed to get group membership history")
+		}
+
+		grant := grant.NewGrant(resource, memberEntitlement, userResource.Id, withLatestChange(change))
 		rv = append(rv, grant)
 	}
 
@@ -113,16 +128,78 @@ func (g *groupResourceType) Grants(ctx context.Context, resource *v2.Resource, p
 	return rv, nextPage, annos, nil
 }
 
-func newGroupBuilder(client *snipeit.Client) *groupResourceType {
+// grantsFromMembershipIndex serves the group's grants from memberships computed in one pass over the user list,
+// instead of listing users filtered by group for every group.
+func (g *groupResourceType) grantsFromMembershipIndex(
+	ctx context.Context,
+	resource *v2.Resource,
+	groupID int,
+	bag *pagination.Bag,
+	offset int,
+) ([]*v2.Grant, string, annotations.Annotations, error) {
+	annos := annotations.Annotations{}
+	members, rldata, err := g.users.GroupMembers(ctx, groupID)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	if err != nil {
+		return nil, "", annos, wrapError(err, "Failed to get group members")
+	}
+
+	end := offset + resourcePageSize
+	if end > len(members) {
+		end = len(members)
+	}
+
+	var rv []*v2.Grant
+	for _, userID := range members[min(offset, end):end] {
+		principalID, err := rs.NewResourceID(resourceTypeUser, userID)
+		if err != nil {
+			return nil, "", annos, err
+		}
+
+		change, rldata, err := g.history.Latest(ctx, principalID, groupSubject(groupID))
+		if rldata != nil {
+			annos.Append(rldata)
+		}
+		if err != nil {
+			return nil, "", annos, wrapError(err, "Failed to get group membership history")
+		}
+
+		grant := grant.NewGrant(resource, memberEntitlement, principalID, withLatestChange(change))
+		rv = append(rv, grant)
+	}
+
+	if end >= len(members) {
+		return rv, "", annos, nil
+	}
+
+	nextPage, err := handleNextPage(bag, end)
+	if err != nil {
+		return nil, "", annos, err
+	}
+
+	return rv, nextPage, annos, nil
+}
+
+func newGroupBuilder(client *snipeit.Client, users *userCache, membershipFromUsers bool, history *grantHistory) *groupResourceType {
 	return &groupResourceType{
-		resourceType: resourceTypeGroup,
-		client:       client,
+		resourceType:        resourceTypeGroup,
+		client:              client,
+		users:               users,
+		membershipFromUsers: membershipFromUsers,
+		history:             history,
 	}
 }
 
-func (g *groupResourceType) List(ctx context.Context, _ *v2.ResourceId, pt *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+func (g *groupResourceType) List(ctx context.Context, parentResourceID *v2.ResourceId, pt *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	// Groups are listed under the app.
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
 	annos := annotations.Annotations{}
-	groups, rldata, err := g.client.GetAllGroups(ctx)
+	groups, rldata, err := g.users.Groups(ctx)
 	if rldata != nil {
 		annos.Append(rldata)
 	}
@@ -131,9 +208,9 @@ func (g *groupResourceType) List(ctx context.Context, _ *v2.ResourceId, pt *pagi
 	}
 
 	var resources []*v2.Resource
-	for _, group := range groups.Rows {
+	for _, group := range groups {
 		group := group
-		resource, err := groupResource(ctx, &group)
+		resource, err := groupResource(ctx, &group, rs.WithParentResourceID(parentResourceID))
 		if err != nil {
 			return nil, "", annos, err
 		}
@@ -181,7 +258,12 @@ func (g *groupResourceType) Grant(ctx context.Context, principal *v2.Resource, e
 		)
 	}
 
-	err = g.client.AddUserToGroup(ctx, groupID, userID)
+	annos := annotations.Annotations{}
+	rldata, err := g.client.AddUserToGroup(ctx, groupID, userID)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	g.users.Reset(ctx)
 	if err != nil {
 		err := wrapError(err, "baton-snipe-it: failed to add user to group")
 
@@ -192,7 +274,7 @@ func (g *groupResourceType) Grant(ctx context.Context, principal *v2.Resource, e
 		)
 	}
 
-	return nil, nil
+	return annos, nil
 }
 
 func (g *groupResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
@@ -235,7 +317,12 @@ func (g *groupResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annota
 		)
 	}
 
-	err = g.client.RemoveUserFromGroup(ctx, groupID, userID)
+	annos := annotations.Annotations{}
+	rldata, err := g.client.RemoveUserFromGroup(ctx, groupID, userID)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	g.users.Reset(ctx)
 	if err != nil {
 		err := wrapError(err, "baton-snipe-it: failed to remove user from group")
 
@@ -246,5 +333,5 @@ func (g *groupResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annota
 		)
 	}
 
-	return nil, nil
+	return annos, nil
 }
@@ -0,0 +1,219 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	snipeit "github.com/conductorone/baton-snipe-it/pkg/snipe-it"
+)
+
+const (
+	// activityPageSize is the page size used to walk the activity log, which is usually much longer than the user list.
+	activityPageSize = 500
+
+	// DefaultGrantHistoryLookback is how far back the activity log is read for grant changes by default.
+	DefaultGrantHistoryLookback = 90 * 24 * time.Hour
+)
+
+type (
+	// grantHistory knows, for every user and group, who last changed each of its permissions and group
+	// memberships, reconstructed once per sync from the updates in the activity log within the lookback.
+	grantHistory struct {
+		client   *snipeit.Client
+		lookback time.Duration
+
+		mu      sync.Mutex
+		changes map[string]grantChange
+	}
+
+	// grantChange is the latest activity that changed a permission or group membership.
+	grantChange struct {
+		activityID int
+		actorID    int
+		actorName  string
+		at         time.Time
+	}
+)
+
+func newGrantHistory(client *snipeit.Client, lookback time.Duration) *grantHistory {
+	if lookback <= 0 {
+		lookback = DefaultGrantHistoryLookback
+	}
+
+	return &grantHistory{
+		client:   client,
+		lookback: lookback,
+	}
+}
+
+// Reset drops the history, so the next sync reads the activity log again. A nil history is disabled.
+func (h *grantHistory) Reset() {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.changes = nil
+}
+
+// Latest returns the most recent change of any of the subjects, permission keys or groupSubject(id), held by
+// the principal. It returns nil when the history is disabled or has no record of them within the lookback.
+func (h *grantHistory) Latest(ctx context.Context, principal *v2.ResourceId, subjects ...string) (*grantChange, *v2.RateLimitDescription, error) {
+	if h == nil {
+		return nil, nil, nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var rldata *v2.RateLimitDescription
+	if h.changes == nil {
+		var err error
+		rldata, err = h.load(ctx)
+		if err != nil {
+			return nil, rldata, err
+		}
+	}
+
+	var rv *grantChange
+	for _, subject := range subjects {
+		change, ok := h.changes[historyKey(principal.ResourceType, principal.Resource, subject)]
+		if ok && (rv == nil || change.activityID > rv.activityID) {
+			change := change
+			rv = &change
+		}
+	}
+
+	return rv, rldata, nil
+}
+
+// load walks the updates in the activity log newest first, keeping the first change of every subject, and stops
+// at the first update older than the lookback.
+func (h *grantHistory) load(ctx context.Context) (*v2.RateLimitDescription, error) {
+	changes := make(map[string]grantChange)
+	since := time.Now().Add(-h.lookback)
+
+	it := snipeit.NewIterator[snipeit.Activity](
+		h.client,
+		snipeit.EndpointActivity,
+		activityPageSize,
+		snipeit.WithActionType(snipeit.ActionUpdate),
+		snipeit.WithSort("id"),
+		snipeit.WithOrder(snipeit.SortDescending),
+	)
+	for it.Next(ctx) {
+		activity := it.Value()
+		// Ids follow the order updates were logged in, so everything after this one is older still.
+		if activity.CreatedAt.Before(since) {
+			break
+		}
+		if activity.Item == nil || len(activity.LogMeta) == 0 {
+			continue
+		}
+
+		var resourceType string
+		switch activity.Item.Type {
+		case snipeit.ItemTypeUser:
+			resourceType = resourceTypeUser.Id
+		case snipeit.ItemTypeGroup:
+			resourceType = resourceTypeGroup.Id
+		default:
+			continue
+		}
+
+		change := grantChange{
+			activityID: activity.ID,
+			at:         activity.OccurredAt(),
+		}
+		if actor := activity.Actor(); actor != nil {
+			change.actorID = actor.ID
+			change.actorName = actor.Name
+		}
+
+		var subjects []string
+
+		granted, revoked, err := activity.PermissionChanges()
+		if err != nil {
+			return it.RateLimit(), err
+		}
+		subjects = append(subjects, granted...)
+		subjects = append(subjects, revoked...)
+
+		added, removed, err := activity.GroupChanges()
+		if err != nil {
+			return it.RateLimit(), err
+		}
+		for _, groupID := range append(added, removed...) {
+			subjects = append(subjects, groupSubject(groupID))
+		}
+
+		principalID := strconv.Itoa(activity.Item.ID)
+		for _, subject := range subjects {
+			key := historyKey(resourceType, principalID, subject)
+			if _, ok := changes[key]; !ok {
+				changes[key] = change
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return it.RateLimit(), err
+	}
+
+	h.changes = changes
+
+	return it.RateLimit(), nil
+}
+
+func historyKey(resourceType string, resourceID string, subject string) string {
+	return fmt.Sprintf("%s:%s|%s", resourceType, resourceID, subject)
+}
+
+// groupSubject is the history subject of a membership of the group.
+func groupSubject(groupID int) string {
+	return fmt.Sprintf("group:%d", groupID)
+}
+
+// withLatestChange adds who last changed the grant, and when, to the grant's metadata, keeping any metadata
+// already set.
+func withLatestChange(change *grantChange) grant.GrantOption {
+	return func(g *v2.Grant) error {
+		if change == nil {
+			return nil
+		}
+
+		annos := annotations.Annotations(g.Annotations)
+		metadata := &v2.GrantMetadata{}
+		_, err := annos.Pick(metadata)
+		if err != nil {
+			return err
+		}
+		if metadata.Metadata == nil {
+			metadata.Metadata = &structpb.Struct{}
+		}
+		if metadata.Metadata.Fields == nil {
+			metadata.Metadata.Fields = make(map[string]*structpb.Value)
+		}
+
+		metadata.Metadata.Fields["changed_at"] = structpb.NewStringValue(change.at.Format(time.RFC3339))
+		metadata.Metadata.Fields["change_activity_id"] = structpb.NewStringValue(strconv.Itoa(change.activityID))
+		if change.actorID != 0 {
+			metadata.Metadata.Fields["changed_by"] = structpb.NewStringValue(change.actorName)
+			metadata.Metadata.Fields["changed_by_id"] = structpb.NewStringValue(strconv.Itoa(change.actorID))
+		}
+
+		annos.Update(metadata)
+		g.Annotations = annos
+
+		return nil
+	}
+}
@@ -0,0 +1,232 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	snipeit "github.com/conductorone/baton-snipe-it/pkg/snipe-it"
+)
+
+// stateFileMode keeps the state file, and the user pages spilled to disk, private to the connector's user.
+const stateFileMode = 0o600
+
+// userSyncState is what an incremental user sync remembers between syncs: every user as of the previous
+// sync and the newest updated_at seen, so the next sync only has to fetch users changed since then.
+type userSyncState struct {
+	HighWaterMark time.Time      `json:"high_water_mark"`
+	Users         []snipeit.User `json:"users"`
+}
+
+func loadUserSyncState(path string) (*userSyncState, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	state := &userSyncState{}
+	err = json.NewDecoder(f).Decode(state)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// save writes the state next to its final location first, so an interrupted write never leaves a
+// truncated state file behind. The state holds the permissions and emails of every user, so only its owner
+// may read it.
+func (s *userSyncState) save(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Nothing is left to remove once the file has been renamed into place.
+		_ = os.Remove(f.Name())
+	}()
+
+	err = f.Chmod(stateFileMode)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	err = json.NewEncoder(f).Encode(s)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// primeFromPreviousSync loads the users of the previous sync, fetches only the users created, updated or
+// deleted since its high-water mark and primes the cache with the merged list. It runs once per sync, before
+// the unfiltered user list is first needed, whichever resource needs it first. Without a previous state it
+// does nothing and the sync falls back to listing every user.
+func (c *userCache) primeFromPreviousSync(ctx context.Context) (*v2.RateLimitDescription, error) {
+	l := ctxzap.Extract(ctx)
+
+	c.primeMu.Lock()
+	defer c.primeMu.Unlock()
+
+	c.mu.Lock()
+	primed := c.primed
+	c.mu.Unlock()
+	if primed {
+		return nil, nil
+	}
+
+	state, err := loadUserSyncState(c.stateFile)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		l.Info("baton-snipe-it: no previous user sync state, listing every user", zap.String("state_file", c.stateFile))
+		c.setPrimed()
+		return nil, nil
+	}
+
+	updated, rldata, err := c.client.GetUsersUpdatedSince(ctx, state.HighWaterMark, false, resourcePageSize)
+	if err != nil {
+		return rldata, err
+	}
+
+	deleted, deletedRldata, err := c.client.GetUsersUpdatedSince(ctx, state.HighWaterMark, true, resourcePageSize)
+	if deletedRldata != nil {
+		rldata = deletedRldata
+	}
+	if err != nil {
+		return rldata, err
+	}
+
+	users := make(map[int]snipeit.User, len(state.Users))
+	for _, user := range state.Users {
+		users[user.ID] = user
+	}
+	for _, user := range updated {
+		users[user.ID] = user
+	}
+	for _, user := range deleted {
+		delete(users, user.ID)
+	}
+
+	merged := make([]snipeit.User, 0, len(users))
+	for _, user := range users {
+		merged = append(merged, user)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].ID < merged[j].ID
+	})
+
+	membershipRldata, err := c.refreshMemberships(ctx, merged)
+	if membershipRldata != nil {
+		rldata = membershipRldata
+	}
+	if err != nil {
+		return rldata, err
+	}
+
+	l.Info(
+		"baton-snipe-it: incremental user sync",
+		zap.Time("since", state.HighWaterMark),
+		zap.Int("updated", len(updated)),
+		zap.Int("deleted", len(deleted)),
+		zap.Int("total", len(merged)),
+	)
+
+	err = c.Prime(merged, resourcePageSize)
+	if err != nil {
+		return rldata, err
+	}
+	c.setPrimed()
+
+	return rldata, nil
+}
+
+func (c *userCache) setPrimed() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.primed = true
+}
+
+// refreshMemberships replaces the groups of the users with their current memberships. Adding a user to a group
+// doesn't change its updated_at, so the groups remembered from the previous sync can be stale. The members of
+// every group are listed instead of every user.
+func (c *userCache) refreshMemberships(ctx context.Context, users []snipeit.User) (*v2.RateLimitDescription, error) {
+	groups, rldata, err := c.Groups(ctx)
+	if err != nil {
+		return rldata, err
+	}
+
+	memberships := make(map[int][]snipeit.Group)
+	for _, group := range groups {
+		it := snipeit.NewIterator[snipeit.User](c.client, snipeit.EndpointUsers, resourcePageSize, snipeit.WithGroupId(group.ID))
+		for it.Next(ctx) {
+			userID := it.Value().ID
+			memberships[userID] = append(memberships[userID], snipeit.Group{ID: group.ID, Name: group.Name})
+		}
+		if it.RateLimit() != nil {
+			rldata = it.RateLimit()
+		}
+		if it.Err() != nil {
+			return rldata, it.Err()
+		}
+	}
+
+	for i := range users {
+		groups := memberships[users[i].ID]
+		users[i].Groups = snipeit.GroupsResponse{Total: len(groups), Rows: groups}
+	}
+
+	return rldata, nil
+}
+
+// SaveSyncState records every user listed in this sync, all of which are served from the cache by now,
+// along with the newest updated_at among them. It does nothing unless the user sync is incremental.
+func (c *userCache) SaveSyncState(ctx context.Context) error {
+	if c.stateFile == "" {
+		return nil
+	}
+
+	state := &userSyncState{}
+
+	for offset := 0; ; offset += resourcePageSize {
+		users, _, err := c.GetUsers(ctx, offset, resourcePageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range users.Rows {
+			if user.UpdatedAt.After(state.HighWaterMark) {
+				state.HighWaterMark = user.UpdatedAt.Time
+			}
+		}
+		state.Users = append(state.Users, users.Rows...)
+
+		if isLastPage(len(users.Rows), resourcePageSize) {
+			break
+		}
+	}
+
+	return state.save(c.stateFile)
+}
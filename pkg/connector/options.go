@@ -0,0 +1,132 @@
+package connector
+
+import (
+	"time"
+
+	snipeit "github.com/conductorone/baton-snipe-it/pkg/snipe-it"
+)
+
+// Option configures optional behaviour of the connector.
+type Option func(*SnipeIt)
+
+// WithMaxAttempts sets how many times a throttled or temporarily unavailable request is sent before giving up.
+func WithMaxAttempts(maxAttempts int) Option {
+	return func(d *SnipeIt) {
+		d.clientOptions = append(d.clientOptions, snipeit.WithMaxAttempts(maxAttempts))
+	}
+}
+
+// WithRequestsPerMinute caps how many requests per minute are sent to Snipe-IT. Zero follows the server's advertised limit.
+func WithRequestsPerMinute(requestsPerMinute int) Option {
+	return func(d *SnipeIt) {
+		d.clientOptions = append(d.clientOptions, snipeit.WithRequestsPerMinute(requestsPerMinute))
+	}
+}
+
+// WithMaxConcurrency caps how many requests are in flight at once. Zero means no limit.
+func WithMaxConcurrency(maxConcurrency int) Option {
+	return func(d *SnipeIt) {
+		d.clientOptions = append(d.clientOptions, snipeit.WithMaxConcurrency(maxConcurrency))
+	}
+}
+
+// WithTimezone sets the IANA timezone the instance runs in, e.g. Europe/Berlin. Snipe-IT renders timestamps in
+// its own timezone without an offset, so they are read as UTC unless it is set.
+func WithTimezone(timezone string) Option {
+	return func(d *SnipeIt) {
+		d.timezone = timezone
+	}
+}
+
+// WithGroupMembershipFromUsers computes the members of every group in a single pass over the user list
+// instead of listing users once per group.
+func WithGroupMembershipFromUsers(enabled bool) Option {
+	return func(d *SnipeIt) {
+		d.membershipFromUsers = enabled
+	}
+}
+
+// WithIncrementalUserSync keeps the users of every sync in stateFile and, on the next sync, only fetches the
+// users changed since then. Group memberships don't change a user's updated_at, so they are listed group by
+// group on every sync and replace the memberships remembered in the state file. The file holds full user
+// records and is only readable by its owner.
+func WithIncrementalUserSync(stateFile string) Option {
+	return func(d *SnipeIt) {
+		d.userStateFile = stateFile
+	}
+}
+
+// WithEffectivePermissions grants users the permissions they effectively hold, resolving user denials, group
+// grants and the superuser and admin roles the way Snipe-IT does, instead of mirroring raw permission values.
+func WithEffectivePermissions(enabled bool) Option {
+	return func(d *SnipeIt) {
+		d.effectivePermissions = enabled
+	}
+}
+
+// WithPermissionsConfig loads the YAML file at path to define the modeled roles and override the risk and
+// sensitivity tags of permissions.
+func WithPermissionsConfig(path string) Option {
+	return func(d *SnipeIt) {
+		d.permissionsConfigFile = path
+	}
+}
+
+// WithServiceAccountPattern classifies users whose username matches the regular expression as service accounts,
+// in addition to those holding API tokens.
+func WithServiceAccountPattern(pattern string) Option {
+	return func(d *SnipeIt) {
+		d.serviceAccountPattern = pattern
+	}
+}
+
+// WithServiceAccountNoLoginAge classifies users that may log in, but never logged in to the web UI although
+// they were created at least age ago, as service accounts. Zero, the default, disables the rule, as users
+// signing in through SSO may never get a last login.
+func WithServiceAccountNoLoginAge(age time.Duration) Option {
+	return func(d *SnipeIt) {
+		d.serviceAccountNoLogin = age
+	}
+}
+
+// WithServiceAccountGroupAPITokens also classifies users that may create API tokens through one of their groups,
+// rather than only those granted it directly, as service accounts.
+func WithServiceAccountGroupAPITokens(enabled bool) Option {
+	return func(d *SnipeIt) {
+		d.serviceAccountGroupAPI = enabled
+	}
+}
+
+// WithCustomFields copies custom field values into the profiles of assets, Snipe-IT has no custom fields on users.
+// Every mapping is a field=profile_key pair, the field being its name or database column. Listing the fields to
+// tell encrypted ones apart requires customfields.view, without it mapped fields are skipped.
+func WithCustomFields(mappings []string) Option {
+	return func(d *SnipeIt) {
+		d.customFieldMappings = mappings
+	}
+}
+
+// WithEncryptedCustomFields also copies encrypted custom fields, which are left out by default. Their values are
+// only readable with the assets.view.encrypted_custom_fields permission.
+func WithEncryptedCustomFields(allowed bool) Option {
+	return func(d *SnipeIt) {
+		d.allowEncryptedCustomFields = allowed
+	}
+}
+
+// WithGrantHistory reconstructs who changed the permissions and group memberships of every user and group from
+// the activity log, and records the latest change on permission, role and group grants.
+func WithGrantHistory(enabled bool) Option {
+	return func(d *SnipeIt) {
+		d.grantHistory = enabled
+	}
+}
+
+// WithGrantHistoryLookback limits the grant history to the updates logged within the lookback, so every sync
+// reads a bounded part of the activity log. Grants last changed before then get no change metadata. Zero uses
+// DefaultGrantHistoryLookback.
+func WithGrantHistoryLookback(lookback time.Duration) Option {
+	return func(d *SnipeIt) {
+		d.grantHistoryLookback = lookback
+	}
+}
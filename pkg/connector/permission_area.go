@@ -0,0 +1,510 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+
+	snipeit "github.com/conductorone/baton-snipe-it/pkg/snipe-it"
+)
+
+var resourceTypePermissionArea = &v2.ResourceType{
+	Id:          "permission_area",
+	DisplayName: "Permission Area",
+	Description: "A group of related permissions in Snipe-IT, such as Assets or Licenses",
+}
+
+// permissionAreaResourceType models every non-role permission as an entitlement of the area it belongs to,
+// so each permission shows up exactly once no matter how many users and groups hold it.
+type permissionAreaResourceType struct {
+	resourceType         *v2.ResourceType
+	client               *snipeit.Client
+	users                *userCache
+	effectivePermissions bool
+	config               *permissionsConfig
+	history              *grantHistory
+}
+
+func (p *permissionAreaResourceType) ResourceType(ctx context.Context) *v2.ResourceType {
+	return p.resourceType
+}
+
+func permissionAreaResource(ctx context.Context, area string, opts ...rs.ResourceOption) (*v2.Resource, error) {
+	displayName, ok := permissionAreaNames[area]
+	if !ok {
+		displayName = area
+	}
+
+	resource, err := rs.NewResource(displayName, resourceTypePermissionArea, area, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+func (p *permissionAreaResourceType) List(ctx context.Context, parentResourceID *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	// Permission areas are listed under the app.
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	annos := annotations.Annotations{}
+
+	keys, rldata, err := p.permissionKeys(ctx)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	if err != nil {
+		return nil, "", annos, wrapError(err, "Failed to get permissions")
+	}
+
+	var rv []*v2.Resource
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		area := permissionArea(key)
+		if seen[area] {
+			continue
+		}
+		seen[area] = true
+
+		resource, err := permissionAreaResource(ctx, area, rs.WithParentResourceID(parentResourceID))
+		if err != nil {
+			return nil, "", annos, wrapError(err, "Failed to get permission area resource")
+		}
+
+		rv = append(rv, resource)
+	}
+
+	return rv, "", annos, nil
+}
+
+func (p *permissionAreaResourceType) Entitlements(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	annos := annotations.Annotations{}
+
+	keys, rldata, err := p.permissionKeys(ctx)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	if err != nil {
+		return nil, "", annos, wrapError(err, "Failed to get permissions")
+	}
+
+	var rv []*v2.Entitlement
+	seen := make(map[string]bool)
+	for _, key := range keysInArea(keys, resource.Id.Resource) {
+		entitlement, err := newPermissionEntitlement(resource, key, p.config, seen)
+		if err != nil {
+			return nil, "", annos, wrapError(err, "Failed to get permission entitlement")
+		}
+		if entitlement != nil {
+			rv = append(rv, entitlement)
+		}
+	}
+
+	return rv, "", annos, nil
+}
+
+func (p *permissionAreaResourceType) Grants(ctx context.Context, resource *v2.Resource, pagination *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	annos := annotations.Annotations{}
+	bag, offset, err := parsePageToken(
+		pagination.Token,
+		&v2.ResourceId{ResourceType: resourceTypeUser.Id},
+	)
+	if err != nil {
+		return nil, "", annos, err
+	}
+
+	if p.effectivePermissions {
+		return p.effectiveGrants(ctx, resource, bag, offset)
+	}
+
+	var rv []*v2.Grant
+
+	if offset == 0 {
+		groups, rldata, err := p.users.Groups(ctx)
+		if rldata != nil {
+			annos.Append(rldata)
+		}
+		if err != nil {
+			return nil, "", annos, wrapError(err, "Failed to get groups")
+		}
+
+		for _, group := range groups {
+			group := group
+			groupResource, err := groupResource(ctx, &group)
+			if err != nil {
+				return nil, "", annos, wrapError(err, "Failed to get group resource")
+			}
+
+			grants, rldata, err := p.getPermissionGrants(ctx, group.Permissions, resource, groupResource.Id)
+			if rldata != nil {
+				annos.Append(rldata)
+			}
+			if err != nil {
+				return nil, "", annos, wrapError(err, "Failed to get permission history")
+			}
+
+			for _, g := range grants {
+				annos := annotations.Annotations(g.Annotations)
+				annos.Update(&v2.GrantExpandable{
+					EntitlementIds: []string{
+						ent.NewEntitlementID(groupResource, memberEntitlement),
+					},
+					Shallow:         true,
+					ResourceTypeIds: []string{resourceTypeUser.Id},
+				})
+				g.Annotations = annos
+				rv = append(rv, g)
+			}
+		}
+
+		grants, rldata, err := p.getImpliedGrants(ctx, resource)
+		if rldata != nil {
+			annos.Append(rldata)
+		}
+		if err != nil {
+			return nil, "", annos, wrapError(err, "Failed to get role grants")
+		}
+		rv = append(rv, grants...)
+	}
+
+	users, rldata, err := p.users.GetUsers(ctx, offset, resourcePageSize)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	if err != nil {
+		return nil, "", annos, wrapError(err, "Failed to get users")
+	}
+
+	for _, user := range users.Rows {
+		principalID, err := rs.NewResourceID(resourceTypeUser, user.ID)
+		if err != nil {
+			return nil, "", annos, wrapError(err, "Failed to get user resource id")
+		}
+
+		grants, rldata, err := p.getPermissionGrants(ctx, user.Permissions, resource, principalID)
+		if rldata != nil {
+			annos.Append(rldata)
+		}
+		if err != nil {
+			return nil, "", annos, wrapError(err, "Failed to get permission history")
+		}
+		rv = append(rv, grants...)
+	}
+
+	if isLastPage(len(users.Rows), resourcePageSize) {
+		return rv, "", annos, nil
+	}
+
+	nextPage, err := handleNextPage(bag, offset+resourcePageSize)
+	if err != nil {
+		return nil, "", annos, err
+	}
+
+	return rv, nextPage, annos, nil
+}
+
+// effectiveGrants grants every user the permissions of the area it effectively holds once user denials, group
+// grants and the superuser and admin roles are taken into account. Groups and roles get no grants of their own
+// in this mode, each grant is annotated with where the permission comes from instead.
+func (p *permissionAreaResourceType) effectiveGrants(ctx context.Context, resource *v2.Resource, bag *pagination.Bag, offset int) ([]*v2.Grant, string, annotations.Annotations, error) {
+	annos := annotations.Annotations{}
+
+	groups, rldata, err := p.users.Groups(ctx)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	if err != nil {
+		return nil, "", annos, wrapError(err, "Failed to get groups")
+	}
+	resolver := snipeit.NewPermissionResolver(groups)
+
+	keys, rldata, err := p.permissionKeys(ctx)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	if err != nil {
+		return nil, "", annos, wrapError(err, "Failed to get permissions")
+	}
+	keys = keysInArea(keys, resource.Id.Resource)
+
+	users, rldata, err := p.users.GetUsers(ctx, offset, resourcePageSize)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	if err != nil {
+		return nil, "", annos, wrapError(err, "Failed to get users")
+	}
+
+	var rv []*v2.Grant
+	for _, user := range users.Rows {
+		user := user
+		principalID, err := rs.NewResourceID(resourceTypeUser, user.ID)
+		if err != nil {
+			return nil, "", annos, wrapError(err, "Failed to get user resource id")
+		}
+
+		for _, permission := range resolver.Effective(&user, keys) {
+			// The change that matters is the one on whoever the permission was granted to, the user or its group.
+			changedID := principalID
+			if permission.Source == snipeit.PermissionSourceGroup {
+				changedID, err = rs.NewResourceID(resourceTypeGroup, permission.GroupID)
+				if err != nil {
+					return nil, "", annos, wrapError(err, "Failed to get group resource id")
+				}
+			}
+
+			change, rldata, err := p.history.Latest(ctx, changedID, permission.Key)
+			if rldata != nil {
+				annos.Append(rldata)
+			}
+			if err != nil {
+				return nil, "", annos, wrapError(err, "Failed to get permission history")
+			}
+
+			rv = append(rv, grant.NewGrant(
+				resource,
+				permission.Key,
+				principalID,
+				withPermissionSource(permission),
+				withLatestChange(change),
+			))
+		}
+	}
+
+	if isLastPage(len(users.Rows), resourcePageSize) {
+		return rv, "", annos, nil
+	}
+
+	nextPage, err := handleNextPage(bag, offset+resourcePageSize)
+	if err != nil {
+		return nil, "", annos, err
+	}
+
+	return rv, nextPage, annos, nil
+}
+
+// getImpliedGrants grants the admin roles, such as Superuser and Admin, every permission of the area they imply,
+// expandable onto the holders of each role, so everybody who can perform an action shows up on its entitlement.
+// The expansion is deep so holders who get a role through one of their groups are included too.
+func (p *permissionAreaResourceType) getImpliedGrants(ctx context.Context, resource *v2.Resource) ([]*v2.Grant, *v2.RateLimitDescription, error) {
+	keys, rldata, err := p.permissionKeys(ctx)
+	if err != nil {
+		return nil, rldata, err
+	}
+	keys = keysInArea(keys, resource.Id.Resource)
+
+	var rv []*v2.Grant
+	for _, role := range p.config.roles() {
+		if !role.Admin {
+			continue
+		}
+
+		roleResource, err := roleResource(ctx, role)
+		if err != nil {
+			return nil, rldata, err
+		}
+
+		for _, key := range keys {
+			if !role.implies(key) {
+				continue
+			}
+
+			rv = append(rv, grant.NewGrant(resource, key, roleResource.Id, grant.WithAnnotation(&v2.GrantExpandable{
+				EntitlementIds: []string{
+					ent.NewEntitlementID(roleResource, assignedEntitlement),
+				},
+				Shallow:         false,
+				ResourceTypeIds: []string{resourceTypeUser.Id},
+			})))
+		}
+	}
+
+	return rv, rldata, nil
+}
+
+// permissionKeys returns the catalogued permission keys followed by the ones granted to somebody that are not
+// in the catalogue, e.g. from a customized instance. Keys modeled by admin roles are left out.
+func (p *permissionAreaResourceType) permissionKeys(ctx context.Context) ([]string, *v2.RateLimitDescription, error) {
+	_, rldata, err := p.client.Version(ctx)
+	if err != nil {
+		return nil, rldata, err
+	}
+	apiVersion := p.client.APIVersion()
+
+	granted, rldata, err := p.users.GrantedPermissions(ctx)
+	if err != nil {
+		return nil, rldata, err
+	}
+
+	var rv []string
+	for _, permission := range permissionCatalogue {
+		if permission.since > apiVersion {
+			continue
+		}
+
+		if !p.config.isAdminPermission(permission.key) {
+			rv = append(rv, permission.key)
+		}
+	}
+
+	for _, key := range granted {
+		if _, ok := permissionsByKey[key]; ok || p.config.isAdminPermission(key) {
+			continue
+		}
+
+		rv = append(rv, key)
+	}
+
+	return rv, rldata, nil
+}
+
+// getPermissionGrants grants the principal every permission of the area it was explicitly granted, along with
+// who last changed each permission when the grant history is enabled.
+func (p *permissionAreaResourceType) getPermissionGrants(
+	ctx context.Context,
+	permissions snipeit.Permissions,
+	areaResource *v2.Resource,
+	principalID *v2.ResourceId,
+) ([]*v2.Grant, *v2.RateLimitDescription, error) {
+	var (
+		rv     []*v2.Grant
+		rldata *v2.RateLimitDescription
+	)
+
+	for permission, value := range permissions {
+		if value != snipeit.Granted || p.config.isAdminPermission(permission) || permissionArea(permission) != areaResource.Id.Resource {
+			continue
+		}
+
+		change, rl, err := p.history.Latest(ctx, principalID, permission)
+		if rl != nil {
+			rldata = rl
+		}
+		if err != nil {
+			return nil, rldata, err
+		}
+
+		rv = append(rv, grant.NewGrant(areaResource, permission, principalID, withLatestChange(change)))
+	}
+
+	return rv, rldata, nil
+}
+
+// newPermissionEntitlement returns nil if an entitlement for the permission was already returned. The slug is
+// the full permission key, so keys sharing a prefix such as assets.view and assets.view.requestable stay apart.
+func newPermissionEntitlement(resource *v2.Resource, permission string, config *permissionsConfig, seen map[string]bool) (*v2.Entitlement, error) {
+	if seen[permission] {
+		return nil, nil
+	}
+	seen[permission] = true
+
+	displayName, err := permissionDisplayName(permission)
+	if err != nil {
+		return nil, err
+	}
+
+	description := permissionsByKey[permission].description
+	if description == "" {
+		description = fmt.Sprintf("can %s", displayName)
+	}
+
+	assigmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeUser, resourceTypeGroup, resourceTypeRole),
+		ent.WithDescription(description),
+		ent.WithDisplayName(displayName),
+		config.withSensitivity(permission),
+	}
+
+	return ent.NewPermissionEntitlement(resource, permission, assigmentOptions...), nil
+}
+
+func keysInArea(keys []string, area string) []string {
+	var rv []string
+	for _, key := range keys {
+		if permissionArea(key) == area {
+			rv = append(rv, key)
+		}
+	}
+
+	return rv
+}
+
+// permissionArea returns the area a permission key belongs to, its first segment.
+func permissionArea(permission string) string {
+	area, _, _ := strings.Cut(permission, ".")
+
+	return area
+}
+
+// permissionDisplayName returns a readable name for the permission, e.g. "view requestable assets" for
+// assets.view.requestable. Catalogued permissions may override it when the words don't line up.
+func permissionDisplayName(permission string) (string, error) {
+	if name := permissionsByKey[permission].name; name != "" {
+		return name, nil
+	}
+
+	entity, action, qualifiers, err := parsePermission(permission)
+	if err != nil {
+		return "", err
+	}
+
+	if entity == "" {
+		return humanize(action), nil
+	}
+
+	if name, ok := permissionAreaNames[entity]; ok {
+		entity = strings.ToLower(name)
+	}
+
+	words := append([]string{action}, qualifiers...)
+	words = append(words, entity)
+
+	return humanize(strings.Join(words, " ")), nil
+}
+
+// parsePermission splits a permission key into the entity, the action and any further qualifiers of the
+// action. Keys without an action, such as import, are their own action.
+func parsePermission(permission string) (string, string, []string, error) {
+	parts := strings.Split(permission, ".")
+	for _, part := range parts {
+		if part == "" {
+			return "", "", nil, fmt.Errorf("invalid permission: %s", permission)
+		}
+	}
+
+	if len(parts) < 2 {
+		return "", parts[0], nil, nil
+	}
+
+	return parts[0], parts[1], parts[2:], nil
+}
+
+func humanize(s string) string {
+	return strings.ReplaceAll(s, "_", " ")
+}
+
+func newPermissionAreaBuilder(
+	client *snipeit.Client,
+	users *userCache,
+	effectivePermissions bool,
+	config *permissionsConfig,
+	history *grantHistory,
+) *permissionAreaResourceType {
+	return &permissionAreaResourceType{
+		resourceType:         resourceTypePermissionArea,
+		client:               client,
+		users:                users,
+		effectivePermissions: effectivePermissions,
+		config:               config,
+		history:              history,
+	}
+}
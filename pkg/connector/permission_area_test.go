@@ -0,0 +1,217 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/dotc1z"
+	sdkSync "github.com/conductorone/baton-sdk/pkg/sync"
+	"github.com/conductorone/baton-sdk/pkg/types"
+	"google.golang.org/grpc"
+)
+
+// connectorClient serves a connector in process so the syncer can run against it.
+type connectorClient struct {
+	types.ConnectorClient
+	server types.ConnectorServer
+}
+
+func (c connectorClient) ListResourceTypes(ctx context.Context, in *v2.ResourceTypesServiceListResourceTypesRequest, _ ...grpc.CallOption) (*v2.ResourceTypesServiceListResourceTypesResponse, error) {
+	return c.server.ListResourceTypes(ctx, in)
+}
+
+func (c connectorClient) ListResources(ctx context.Context, in *v2.ResourcesServiceListResourcesRequest, _ ...grpc.CallOption) (*v2.ResourcesServiceListResourcesResponse, error) {
+	return c.server.ListResources(ctx, in)
+}
+
+func (c connectorClient) ListEntitlements(ctx context.Context, in *v2.EntitlementsServiceListEntitlementsRequest, _ ...grpc.CallOption) (*v2.EntitlementsServiceListEntitlementsResponse, error) {
+	return c.server.ListEntitlements(ctx, in)
+}
+
+func (c connectorClient) ListGrants(ctx context.Context, in *v2.GrantsServiceListGrantsRequest, _ ...grpc.CallOption) (*v2.GrantsServiceListGrantsResponse, error) {
+	return c.server.ListGrants(ctx, in)
+}
+
+func (c connectorClient) GetMetadata(ctx context.Context, in *v2.ConnectorServiceGetMetadataRequest, _ ...grpc.CallOption) (*v2.ConnectorServiceGetMetadataResponse, error) {
+	return c.server.GetMetadata(ctx, in)
+}
+
+func (c connectorClient) Validate(ctx context.Context, in *v2.ConnectorServiceValidateRequest, _ ...grpc.CallOption) (*v2.ConnectorServiceValidateResponse, error) {
+	return c.server.Validate(ctx, in)
+}
+
+type testUser struct {
+	id          int
+	permissions string
+	groupIDs    []int
+}
+
+func (u testUser) json() string {
+	groups := make([]string, 0, len(u.groupIDs))
+	for _, id := range u.groupIDs {
+		groups = append(groups, fmt.Sprintf(`{"id":%d}`, id))
+	}
+
+	return fmt.Sprintf(
+		`{"id":%d,"username":"user%d","email":"user%d@example.com","activated":true,"permissions":%s,"groups":{"total":%d,"rows":[%s]}}`,
+		u.id, u.id, u.id, u.permissions, len(groups), strings.Join(groups, ","),
+	)
+}
+
+// snipeItServer fakes the parts of the Snipe-IT API a sync reads, with the given groups and users.
+func snipeItServer(t *testing.T, groups string, users []testUser) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/version":
+			_, _ = w.Write([]byte(`{"status":"success","payload":{"version":"v7.0.13"}}`))
+		case "/api/v1/users/me":
+			_, _ = w.Write([]byte(`{"id":99,"username":"admin","permissions":{"superuser":"1"},"groups":{"total":0,"rows":[]}}`))
+		case "/api/v1/groups":
+			_, _ = w.Write([]byte(groups))
+		case "/api/v1/users":
+			query := r.URL.Query()
+			groupID, _ := strconv.Atoi(query.Get("group_id"))
+			offset, _ := strconv.Atoi(query.Get("offset"))
+
+			var rows []string
+			for _, user := range users {
+				if groupID != 0 && !containsInt(user.groupIDs, groupID) {
+					continue
+				}
+				rows = append(rows, user.json())
+			}
+			total := len(rows)
+			if offset >= len(rows) {
+				rows = nil
+			} else {
+				rows = rows[offset:]
+			}
+
+			_, _ = fmt.Fprintf(w, `{"total":%d,"rows":[%s]}`, total, strings.Join(rows, ","))
+		default:
+			_, _ = w.Write([]byte(`{"total":0,"rows":[]}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// syncGrants runs a full sync of the connector, grant expansion included, and returns the entitlements granted
+// to each user.
+func syncGrants(t *testing.T, baseUrl string, opts ...Option) map[string][]string {
+	ctx := context.Background()
+	dir := t.TempDir()
+	c1zPath := filepath.Join(dir, "sync.c1z")
+
+	snipeIt, err := New(ctx, baseUrl, "token", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := connectorbuilder.NewConnector(ctx, snipeIt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	syncer, err := sdkSync.NewSyncer(ctx, connectorClient{server: server}, sdkSync.WithC1ZPath(c1zPath), sdkSync.WithTmpDir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = syncer.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = syncer.Close(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := dotc1z.NewC1ZFile(ctx, c1zPath, dotc1z.WithTmpDir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	rv := make(map[string][]string)
+	pageToken := ""
+	for {
+		res, err := store.ListGrants(ctx, &v2.GrantsServiceListGrantsRequest{PageToken: pageToken})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, g := range res.List {
+			if g.Principal.Id.ResourceType == resourceTypeUser.Id {
+				rv[g.Principal.Id.Resource] = append(rv[g.Principal.Id.Resource], g.Entitlement.Id)
+			}
+		}
+
+		pageToken = res.NextPageToken
+		if pageToken == "" {
+			break
+		}
+	}
+
+	return rv
+}
+
+func TestImpliedGrantsExpandThroughGroups(t *testing.T) {
+	groups := `{"total":2,"rows":[` +
+		`{"id":1,"name":"Staff","permissions":{"users.view":"1"}},` +
+		`{"id":4,"name":"Superusers","permissions":{"superuser":"1"}}]}`
+	users := []testUser{
+		{id: 1, permissions: `{}`, groupIDs: []int{4}},
+		{id: 2, permissions: `{"superuser":"1"}`},
+		{id: 3, permissions: `{}`, groupIDs: []int{1}},
+	}
+
+	grants := syncGrants(t, snipeItServer(t, groups, users).URL)
+
+	tests := []struct {
+		name        string
+		userID      string
+		entitlement string
+		want        bool
+	}{
+		{name: "superuser through a group", userID: "1", entitlement: "permission_area:assets:assets.delete", want: true},
+		{name: "superuser", userID: "2", entitlement: "permission_area:assets:assets.delete", want: true},
+		{name: "group permission", userID: "3", entitlement: "permission_area:users:users.view", want: true},
+		{name: "not implied for other members", userID: "3", entitlement: "permission_area:assets:assets.delete", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := false
+			for _, entitlementID := range grants[tt.userID] {
+				if entitlementID == tt.entitlement {
+					got = true
+					break
+				}
+			}
+
+			if got != tt.want {
+				t.Errorf("user %s granted %s = %v, want %v (grants: %v)", tt.userID, tt.entitlement, got, tt.want, grants[tt.userID])
+			}
+		})
+	}
+}
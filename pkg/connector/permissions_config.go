@@ -0,0 +1,191 @@
+package connector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+
+	snipeitv1 "github.com/conductorone/baton-snipe-it/pb/snipeit/v1"
+)
+
+// Sensitivity tags of the permissions that deserve stronger approval.
+const (
+	sensitivitySuperuser             = "superuser"
+	sensitivityAdmin                 = "admin"
+	sensitivityUserManagement        = "user_management"
+	sensitivityEncryptedCustomFields = "encrypted_custom_fields"
+	sensitivityAPIAccess             = "api_access"
+	sensitivityImports               = "imports"
+)
+
+type (
+	// permissionsConfig customizes the built-in roles and permission catalogue. It is loaded from a YAML file
+	// such as:
+	//
+	//	roles:
+	//	  - name: Superuser
+	//	    permissions: [superuser]
+	//	    admin: true
+	//	  - name: Auditor
+	//	    display_name: Asset auditor
+	//	    permissions: [assets.view, assets.audit]
+	//	permissions:
+	//	  assets.view.encrypted_custom_fields:
+	//	    risk: critical
+	//	    sensitivity: [encrypted_custom_fields]
+	permissionsConfig struct {
+		Roles       []roleDefinition              `yaml:"roles"`
+		Permissions map[string]permissionOverride `yaml:"permissions"`
+	}
+
+	// permissionOverride replaces the risk and sensitivity tags of a permission, fields left out keep the
+	// catalogue's values.
+	permissionOverride struct {
+		Risk        permissionRisk `yaml:"risk"`
+		Sensitivity []string       `yaml:"sensitivity"`
+	}
+)
+
+func loadPermissionsConfig(path string) (*permissionsConfig, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	config := &permissionsConfig{}
+	err = yaml.Unmarshal(data, config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid permissions config %s: %w", path, err)
+	}
+
+	names := make(map[string]bool)
+	for _, role := range config.Roles {
+		if role.Name == "" {
+			return nil, fmt.Errorf("invalid permissions config %s: role without a name", path)
+		}
+		if names[role.Name] {
+			return nil, fmt.Errorf("invalid permissions config %s: duplicate role %s", path, role.Name)
+		}
+		names[role.Name] = true
+
+		if len(role.Permissions) == 0 {
+			return nil, fmt.Errorf("invalid permissions config %s: role %s has no permissions", path, role.Name)
+		}
+	}
+
+	for key, override := range config.Permissions {
+		switch override.Risk {
+		case "", permissionRiskLow, permissionRiskMedium, permissionRiskHigh, permissionRiskCritical:
+		default:
+			return nil, fmt.Errorf("invalid permissions config %s: unknown risk %q for %s", path, override.Risk, key)
+		}
+	}
+
+	return config, nil
+}
+
+// roles returns the configured roles, or the default Superuser and Admin roles.
+func (c *permissionsConfig) roles() []roleDefinition {
+	if c == nil || len(c.Roles) == 0 {
+		return defaultRoles
+	}
+
+	return c.Roles
+}
+
+func (c *permissionsConfig) role(name string) (roleDefinition, bool) {
+	for _, role := range c.roles() {
+		if role.Name == name {
+			return role, true
+		}
+	}
+
+	return roleDefinition{}, false
+}
+
+// isAdminPermission reports whether the permission is modeled by an admin role rather than a permission area.
+func (c *permissionsConfig) isAdminPermission(key string) bool {
+	for _, role := range c.roles() {
+		if role.Admin && contains(role.Permissions, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sensitivity returns the risk and sensitivity tags of the permission, a nil config uses the catalogue as is.
+func (c *permissionsConfig) sensitivity(key string) (permissionRisk, []string) {
+	permission := permissionsByKey[key]
+	risk, tags := permission.risk, permission.sensitivity
+
+	if c != nil {
+		if override, ok := c.Permissions[key]; ok {
+			if override.Risk != "" {
+				risk = override.Risk
+			}
+			if override.Sensitivity != nil {
+				tags = override.Sensitivity
+			}
+		}
+	}
+
+	return risk, tags
+}
+
+// withSensitivity annotates the entitlement of the permission with its risk and sensitivity tags, so policies
+// can tell a superuser apart from somebody viewing assets.
+func (c *permissionsConfig) withSensitivity(key string) ent.EntitlementOption {
+	risk, tags := c.sensitivity(key)
+
+	return ent.WithAnnotation(&snipeitv1.EntitlementSensitivity{
+		Subject:     &snipeitv1.EntitlementSensitivity_Permission{Permission: key},
+		Risk:        riskValues[risk],
+		Sensitivity: tags,
+	})
+}
+
+// withRoleSensitivity annotates the entitlement of the role with the highest risk and every sensitivity tag of
+// its permissions. Admin roles are always tagged as such.
+func (c *permissionsConfig) withRoleSensitivity(name string) ent.EntitlementOption {
+	role, _ := c.role(name)
+
+	var (
+		risk permissionRisk
+		tags []string
+	)
+	for _, key := range role.Permissions {
+		keyRisk, keyTags := c.sensitivity(key)
+		if riskValues[keyRisk] > riskValues[risk] {
+			risk = keyRisk
+		}
+
+		for _, tag := range keyTags {
+			if !contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	if role.Admin && !contains(tags, sensitivitySuperuser) && !contains(tags, sensitivityAdmin) {
+		tags = append(tags, sensitivityAdmin)
+	}
+
+	return ent.WithAnnotation(&snipeitv1.EntitlementSensitivity{
+		Subject:     &snipeitv1.EntitlementSensitivity_Role{Role: name},
+		Risk:        riskValues[risk],
+		Sensitivity: tags,
+	})
+}
+
+// riskValues maps the risk levels of the catalogue and config onto the annotation's, ordered by risk.
+var riskValues = map[permissionRisk]snipeitv1.Risk{
+	permissionRiskLow:      snipeitv1.Risk_RISK_LOW,
+	permissionRiskMedium:   snipeitv1.Risk_RISK_MEDIUM,
+	permissionRiskHigh:     snipeitv1.Risk_RISK_HIGH,
+	permissionRiskCritical: snipeitv1.Risk_RISK_CRITICAL,
+}
@@ -3,6 +3,7 @@ package connector
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -23,32 +24,233 @@ var (
 		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_ROLE},
 	}
 
-	roles      = []string{"Superuser", "Admin", "User", "Import", "Other"}
-	adminRoles = []string{"Superuser", "Admin"}
+	// defaultRoles are the roles modeled when the permissions config doesn't define any.
+	defaultRoles = []roleDefinition{
+		{Name: "Superuser", Permissions: []string{snipeit.PermissionSuperuser}, Admin: true},
+		{Name: "Admin", Permissions: []string{snipeit.PermissionAdmin}, Admin: true},
+	}
+)
+
+type (
+	permissionRisk string
+
+	// roleDefinition describes a role. A role is held by whoever is granted every one of its permission keys.
+	// The keys of admin roles are modeled by the role alone, other roles bundle permissions that keep their own
+	// entitlements.
+	roleDefinition struct {
+		Name        string   `yaml:"name"`
+		DisplayName string   `yaml:"display_name"`
+		Permissions []string `yaml:"permissions"`
+		Admin       bool     `yaml:"admin"`
+	}
+
+	// permissionDefinition describes a Snipe-IT permission key.
+	permissionDefinition struct {
+		key string
+		// name overrides the display name derived from the key.
+		name        string
+		description string
+		risk        permissionRisk
+		sensitivity []string
+		// since is the first Snipe-IT version offering the permission, zero if every supported version does.
+		since snipeit.APIVersion
+	}
+)
 
-	rolesLowerCase      = Map(roles, strings.ToLower)
-	adminRolesLowerCase = Map(adminRoles, strings.ToLower)
+const (
+	permissionRiskLow      permissionRisk = "low"
+	permissionRiskMedium   permissionRisk = "medium"
+	permissionRiskHigh     permissionRisk = "high"
+	permissionRiskCritical permissionRisk = "critical"
 )
 
+// permissionCatalogue lists every permission Snipe-IT v6 and v7 define in config/permissions.php, so their
+// entitlements exist before anybody holds them.
+var permissionCatalogue = []permissionDefinition{
+	// Global
+	{key: "superuser", description: "Full access to everything, including settings and backups", risk: permissionRiskCritical, sensitivity: []string{sensitivitySuperuser}},
+	// Admin
+	{key: "admin", description: "Administer every item and user, except settings", risk: permissionRiskCritical, sensitivity: []string{sensitivityAdmin}},
+	// CSV Import
+	{key: "import", description: "Import CSV files, creating and overwriting records in bulk", risk: permissionRiskHigh, sensitivity: []string{sensitivityImports}},
+	// Reports
+	{key: "reports.view", description: "View reports, including the activity log", risk: permissionRiskMedium},
+	// Assets
+	{key: "assets.view", description: "View assets", risk: permissionRiskLow},
+	{key: "assets.create", description: "Create assets", risk: permissionRiskMedium},
+	{key: "assets.edit", description: "Edit assets", risk: permissionRiskMedium},
+	{key: "assets.delete", description: "Delete assets", risk: permissionRiskHigh},
+	{key: "assets.checkout", description: "Check out assets", risk: permissionRiskMedium},
+	{key: "assets.checkin", description: "Check in assets", risk: permissionRiskMedium},
+	{key: "assets.audit", description: "Audit assets", risk: permissionRiskLow},
+	{key: "assets.view.requestable", description: "View requestable assets", risk: permissionRiskLow},
+	{
+		key:         "assets.view.encrypted_custom_fields",
+		name:        "view encrypted custom fields of assets",
+		description: "View encrypted custom fields of assets",
+		risk:        permissionRiskHigh,
+		sensitivity: []string{sensitivityEncryptedCustomFields},
+	},
+	// Accessories
+	{key: "accessories.view", description: "View accessories", risk: permissionRiskLow},
+	{key: "accessories.create", description: "Create accessories", risk: permissionRiskMedium},
+	{key: "accessories.edit", description: "Edit accessories", risk: permissionRiskMedium},
+	{key: "accessories.delete", description: "Delete accessories", risk: permissionRiskHigh},
+	{key: "accessories.checkout", description: "Check out accessories", risk: permissionRiskMedium},
+	{key: "accessories.checkin", description: "Check in accessories", risk: permissionRiskMedium},
+	{key: "accessories.files", description: "Manage files attached to accessories", risk: permissionRiskMedium, since: snipeit.APIVersion7},
+	// Consumables
+	{key: "consumables.view", description: "View consumables", risk: permissionRiskLow},
+	{key: "consumables.create", description: "Create consumables", risk: permissionRiskMedium},
+	{key: "consumables.edit", description: "Edit consumables", risk: permissionRiskMedium},
+	{key: "consumables.delete", description: "Delete consumables", risk: permissionRiskHigh},
+	{key: "consumables.checkout", description: "Check out consumables", risk: permissionRiskMedium},
+	{key: "consumables.files", description: "Manage files attached to consumables", risk: permissionRiskMedium, since: snipeit.APIVersion7},
+	// Licenses
+	{key: "licenses.view", description: "View licenses", risk: permissionRiskLow},
+	{key: "licenses.create", description: "Create licenses", risk: permissionRiskMedium},
+	{key: "licenses.edit", description: "Edit licenses", risk: permissionRiskMedium},
+	{key: "licenses.delete", description: "Delete licenses", risk: permissionRiskHigh},
+	{key: "licenses.checkout", description: "Check out license seats", risk: permissionRiskMedium},
+	{key: "licenses.keys", description: "View license product keys", risk: permissionRiskHigh},
+	{key: "licenses.files", description: "Manage files attached to licenses", risk: permissionRiskMedium},
+	// Components
+	{key: "components.view", description: "View components", risk: permissionRiskLow},
+	{key: "components.create", description: "Create components", risk: permissionRiskMedium},
+	{key: "components.edit", description: "Edit components", risk: permissionRiskMedium},
+	{key: "components.delete", description: "Delete components", risk: permissionRiskHigh},
+	{key: "components.checkout", description: "Check out components", risk: permissionRiskMedium},
+	{key: "components.checkin", description: "Check in components", risk: permissionRiskMedium},
+	{key: "components.files", description: "Manage files attached to components", risk: permissionRiskMedium, since: snipeit.APIVersion7},
+	// Kits
+	{key: "kits.view", description: "View predefined kits", risk: permissionRiskLow},
+	{key: "kits.create", description: "Create predefined kits", risk: permissionRiskMedium},
+	{key: "kits.edit", description: "Edit predefined kits", risk: permissionRiskMedium},
+	{key: "kits.delete", description: "Delete predefined kits", risk: permissionRiskMedium},
+	{key: "kits.checkout", description: "Check out predefined kits", risk: permissionRiskMedium},
+	// Users
+	{key: "users.view", description: "View users", risk: permissionRiskMedium},
+	{key: "users.create", description: "Create users", risk: permissionRiskHigh, sensitivity: []string{sensitivityUserManagement}},
+	{key: "users.edit", description: "Edit users, including their groups and permissions", risk: permissionRiskHigh, sensitivity: []string{sensitivityUserManagement}},
+	{key: "users.delete", description: "Delete users", risk: permissionRiskHigh, sensitivity: []string{sensitivityUserManagement}},
+	// Models
+	{key: "models.view", description: "View asset models", risk: permissionRiskLow},
+	{key: "models.create", description: "Create asset models", risk: permissionRiskMedium},
+	{key: "models.edit", description: "Edit asset models", risk: permissionRiskMedium},
+	{key: "models.delete", description: "Delete asset models", risk: permissionRiskHigh},
+	// Categories
+	{key: "categories.view", description: "View categories", risk: permissionRiskLow},
+	{key: "categories.create", description: "Create categories", risk: permissionRiskMedium},
+	{key: "categories.edit", description: "Edit categories", risk: permissionRiskMedium},
+	{key: "categories.delete", description: "Delete categories", risk: permissionRiskHigh},
+	// Departments
+	{key: "departments.view", description: "View departments", risk: permissionRiskLow},
+	{key: "departments.create", description: "Create departments", risk: permissionRiskMedium},
+	{key: "departments.edit", description: "Edit departments", risk: permissionRiskMedium},
+	{key: "departments.delete", description: "Delete departments", risk: permissionRiskHigh},
+	// Status Labels
+	{key: "statuslabels.view", description: "View status labels", risk: permissionRiskLow},
+	{key: "statuslabels.create", description: "Create status labels", risk: permissionRiskMedium},
+	{key: "statuslabels.edit", description: "Edit status labels", risk: permissionRiskMedium},
+	{key: "statuslabels.delete", description: "Delete status labels", risk: permissionRiskHigh},
+	// Custom Fields
+	{key: "customfields.view", description: "View custom fields", risk: permissionRiskLow},
+	{key: "customfields.create", description: "Create custom fields", risk: permissionRiskMedium},
+	{key: "customfields.edit", description: "Edit custom fields", risk: permissionRiskHigh},
+	{key: "customfields.delete", description: "Delete custom fields", risk: permissionRiskHigh},
+	// Suppliers
+	{key: "suppliers.view", description: "View suppliers", risk: permissionRiskLow},
+	{key: "suppliers.create", description: "Create suppliers", risk: permissionRiskMedium},
+	{key: "suppliers.edit", description: "Edit suppliers", risk: permissionRiskMedium},
+	{key: "suppliers.delete", description: "Delete suppliers", risk: permissionRiskHigh},
+	// Manufacturers
+	{key: "manufacturers.view", description: "View manufacturers", risk: permissionRiskLow},
+	{key: "manufacturers.create", description: "Create manufacturers", risk: permissionRiskMedium},
+	{key: "manufacturers.edit", description: "Edit manufacturers", risk: permissionRiskMedium},
+	{key: "manufacturers.delete", description: "Delete manufacturers", risk: permissionRiskHigh},
+	// Depreciations
+	{key: "depreciations.view", description: "View depreciations", risk: permissionRiskLow},
+	{key: "depreciations.create", description: "Create depreciations", risk: permissionRiskMedium},
+	{key: "depreciations.edit", description: "Edit depreciations", risk: permissionRiskMedium},
+	{key: "depreciations.delete", description: "Delete depreciations", risk: permissionRiskHigh},
+	// Locations
+	{key: "locations.view", description: "View locations", risk: permissionRiskLow},
+	{key: "locations.create", description: "Create locations", risk: permissionRiskMedium},
+	{key: "locations.edit", description: "Edit locations", risk: permissionRiskMedium},
+	{key: "locations.delete", description: "Delete locations", risk: permissionRiskHigh},
+	// Companies
+	{key: "companies.view", description: "View companies", risk: permissionRiskLow},
+	{key: "companies.create", description: "Create companies", risk: permissionRiskMedium},
+	{key: "companies.edit", description: "Edit companies", risk: permissionRiskHigh},
+	{key: "companies.delete", description: "Delete companies", risk: permissionRiskHigh},
+	// Self
+	{key: "self.two_factor", name: "manage own two-factor authentication", description: "Enroll and reset their own two-factor authentication", risk: permissionRiskLow},
+	{key: "self.api", name: "create own API tokens", description: "Create personal API tokens", risk: permissionRiskHigh, sensitivity: []string{sensitivityAPIAccess}},
+	{key: "self.edit_location", name: "edit own location", description: "Edit their own location", risk: permissionRiskLow},
+	{key: "self.checkout_assets", name: "check out assets to self", description: "Check out assets to themselves", risk: permissionRiskMedium},
+	{key: "self.view_purchase_cost", name: "view purchase cost of own assets", description: "View purchase cost of their own assets", risk: permissionRiskLow},
+}
+
+var permissionsByKey = indexPermissions(permissionCatalogue)
+
+// permissionAreaNames are the display names of the permission areas, keyed by the first segment of their keys.
+var permissionAreaNames = map[string]string{
+	"import":        "CSV Import",
+	"reports":       "Reports",
+	"assets":        "Assets",
+	"accessories":   "Accessories",
+	"consumables":   "Consumables",
+	"licenses":      "Licenses",
+	"components":    "Components",
+	"kits":          "Kits",
+	"users":         "Users",
+	"models":        "Models",
+	"categories":    "Categories",
+	"departments":   "Departments",
+	"statuslabels":  "Status Labels",
+	"customfields":  "Custom Fields",
+	"suppliers":     "Suppliers",
+	"manufacturers": "Manufacturers",
+	"depreciations": "Depreciations",
+	"locations":     "Locations",
+	"companies":     "Companies",
+	"self":          "Self",
+}
+
+func indexPermissions(permissions []permissionDefinition) map[string]permissionDefinition {
+	rv := make(map[string]permissionDefinition, len(permissions))
+	for _, permission := range permissions {
+		rv[permission.key] = permission
+	}
+
+	return rv
+}
+
 type roleResourceType struct {
-	resourceType *v2.ResourceType
-	client       *snipeit.Client
+	resourceType         *v2.ResourceType
+	client               *snipeit.Client
+	users                *userCache
+	effectivePermissions bool
+	config               *permissionsConfig
+	history              *grantHistory
 }
 
 func (r *roleResourceType) ResourceType(ctx context.Context) *v2.ResourceType {
 	return r.resourceType
 }
 
-func roleResource(ctx context.Context, role string) (*v2.Resource, error) {
+func roleResource(ctx context.Context, role roleDefinition, opts ...rs.ResourceOption) (*v2.Resource, error) {
 	profile := map[string]interface{}{
-		"name": role,
+		"name":        role.Name,
+		"permissions": strings.Join(role.Permissions, ","),
+		"admin":       role.Admin,
 	}
 
 	roleTraitOptions := []rs.RoleTraitOption{
 		rs.WithRoleProfile(profile),
 	}
 
-	resource, err := rs.NewRoleResource(role, resourceTypeRole, role, roleTraitOptions)
+	resource, err := rs.NewRoleResource(role.displayName(), resourceTypeRole, role.Name, roleTraitOptions, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -56,127 +258,41 @@ func roleResource(ctx context.Context, role string) (*v2.Resource, error) {
 	return resource, nil
 }
 
-func (r *roleResourceType) Entitlements(ctx context.Context, resource *v2.Resource, pagination *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
-	annos := annotations.Annotations{}
-	bag, offset, err := parsePageToken(
-		pagination.Token,
-		&v2.ResourceId{ResourceType: resourceTypeUser.Id},
-	)
-	if err != nil {
-		return nil, "", annos, err
-	}
-
-	var rv []*v2.Entitlement
-
-	if isAdminRole(resource.Id.Resource) {
-		rv = append(rv, r.getAppointedEntitlement(resource)...)
-
-		return rv, "", annos, nil
-	}
-
-	if offset == 0 {
-		// Groups doesn't have pagination, so we need to get all groups and iterate over them just once
-		groups, rldata, err := r.client.GetAllGroups(ctx)
-		if rldata != nil {
-			annos.Append(rldata)
-		}
-		if err != nil {
-			return nil, "", annos, wrapError(err, "Failed to get groups")
-		}
-
-		for _, group := range groups.Rows {
-			entitlements, err := r.getPermissionEntitlements(group.Permissions, resource, resourceTypeGroup)
-			if err != nil {
-				return nil, "", annos, wrapError(err, "Failed to get group permissions")
-			}
-
-			rv = append(rv, entitlements...)
-		}
-	}
-
-	users, rldata, err := r.client.GetUsers(ctx, offset, resourcePageSize)
-	if rldata != nil {
-		annos.Append(rldata)
-	}
-	if err != nil {
-		return nil, "", annos, wrapError(err, "Failed to get users")
-	}
-
-	for _, user := range users.Rows {
-		entitlements, err := r.getPermissionEntitlements(user.Permissions, resource, resourceTypeUser)
-		if err != nil {
-			return nil, "", annos, wrapError(err, "Failed to get user permissions")
-		}
-
-		rv = append(rv, entitlements...)
-	}
-
-	if isLastPage(len(users.Rows), resourcePageSize) {
-		return rv, "", annos, nil
-	}
-
-	nextPage, err := handleNextPage(bag, offset+resourcePageSize)
-	if err != nil {
-		return nil, "", annos, err
-	}
-
-	return rv, nextPage, annos, nil
+func (r *roleResourceType) Entitlements(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return r.getAppointedEntitlement(resource), "", nil, nil
 }
 
-func (r *roleResourceType) getPermissionEntitlements(permissions snipeit.Permissions, resource *v2.Resource, resourceType *v2.ResourceType) ([]*v2.Entitlement, error) {
-	var rv []*v2.Entitlement
-
-	if isAdminRole(resource.Id.Resource) {
-		return rv, nil
-	}
-
-	for permission, value := range permissions {
-		if value != snipeit.Granted || isRole(permission) {
-			continue
-		}
-
-		entitlementName, err := composePermissionEntitlementName(permission)
-		if err != nil {
-			return nil, err
-		}
-
-		assigmentOptions := []ent.EntitlementOption{
-			ent.WithGrantableTo(resourceType),
-			ent.WithDescription(fmt.Sprintf("can %s", entitlementName)),
-			ent.WithDisplayName(fmt.Sprintf("%s %s", resource.DisplayName, entitlementName)),
-		}
-
-		entitlement := ent.NewPermissionEntitlement(resource, entitlementName, assigmentOptions...)
-		rv = append(rv, entitlement)
+func (r roleDefinition) displayName() string {
+	if r.DisplayName != "" {
+		return r.DisplayName
 	}
 
-	return rv, nil
+	return r.Name
 }
 
-func composePermissionEntitlementName(permission string) (string, error) {
-	entity, action, err := parsePermission(permission)
-	if err != nil {
-		return "", err
+// heldBy reports whether the permissions grant every permission of the role.
+func (r roleDefinition) heldBy(permissions snipeit.Permissions) bool {
+	for _, key := range r.Permissions {
+		if permissions[key] != snipeit.Granted {
+			return false
+		}
 	}
 
-	return fmt.Sprintf("%s %s", action, entity), nil
+	return true
 }
 
-func parsePermission(permission string) (string, string, error) {
-	parts := strings.Split(permission, ".")
-	if len(parts) < 2 {
-		return "", "", fmt.Errorf("invalid permission: %s", permission)
+// implies reports whether holding the role grants the permission, the way Snipe-IT treats superuser and admin.
+func (r roleDefinition) implies(key string) bool {
+	for _, permission := range r.Permissions {
+		switch {
+		case permission == key,
+			permission == snipeit.PermissionSuperuser,
+			permission == snipeit.PermissionAdmin && snipeit.ImpliedByAdmin(key):
+			return true
+		}
 	}
 
-	return parts[0], parts[1], nil
-}
-
-func isRole(input string) bool {
-	return contains(rolesLowerCase, strings.ToLower(input))
-}
-
-func isAdminRole(role string) bool {
-	return contains(adminRolesLowerCase, strings.ToLower(role))
+	return false
 }
 
 func (r *roleResourceType) getAppointedEntitlement(resource *v2.Resource) []*v2.Entitlement {
@@ -186,6 +302,7 @@ func (r *roleResourceType) getAppointedEntitlement(resource *v2.Resource) []*v2.
 		ent.WithGrantableTo(resourceTypeUser),
 		ent.WithDescription(fmt.Sprintf("Appointed to %s role", resource.DisplayName)),
 		ent.WithDisplayName(fmt.Sprintf("%s role %s", resource.DisplayName, assignedEntitlement)),
+		r.config.withRoleSensitivity(resource.Id.Resource),
 	}
 
 	entitlement := ent.NewAssignmentEntitlement(resource, assignedEntitlement, assigmentOptions...)
@@ -195,6 +312,7 @@ func (r *roleResourceType) getAppointedEntitlement(resource *v2.Resource) []*v2.
 		ent.WithGrantableTo(resourceTypeGroup),
 		ent.WithDescription(fmt.Sprintf("Appointed to %s role", resource.DisplayName)),
 		ent.WithDisplayName(fmt.Sprintf("%s role %s", resource.DisplayName, assignedEntitlement)),
+		r.config.withRoleSensitivity(resource.Id.Resource),
 	}
 
 	entitlement = ent.NewAssignmentEntitlement(resource, assignedEntitlement, assigmentOptions...)
@@ -213,11 +331,19 @@ func (r *roleResourceType) Grants(ctx context.Context, resource *v2.Resource, pa
 		return nil, "", annos, err
 	}
 
+	role, ok := r.config.role(resource.Id.Resource)
+	if !ok {
+		return nil, "", annos, fmt.Errorf("baton-snipe-it: unknown role %s", resource.Id.Resource)
+	}
+
+	if r.effectivePermissions {
+		return r.effectiveGrants(ctx, resource, role, bag, offset)
+	}
+
 	var rv []*v2.Grant
 
 	if offset == 0 {
-		// Groups doesn't have pagination, so we need to get all groups and iterate over them just once
-		groups, rldata, err := r.client.GetAllGroups(ctx)
+		groups, rldata, err := r.users.Groups(ctx)
 		if rldata != nil {
 			annos.Append(rldata)
 		}
@@ -225,14 +351,17 @@ func (r *roleResourceType) Grants(ctx context.Context, resource *v2.Resource, pa
 			return nil, "", annos, wrapError(err, "Failed to get groups")
 		}
 
-		for _, group := range groups.Rows {
+		for _, group := range groups {
 			group := group
 			groupResource, err := groupResource(ctx, &group)
 			if err != nil {
 				return nil, "", annos, wrapError(err, "Failed to get group resource")
 			}
 
-			grants, err := r.getGrantsFromPermissions(group.Permissions, resource, groupResource)
+			grants, rldata, err := r.grantRole(ctx, role, group.Permissions, resource, groupResource)
+			if rldata != nil {
+				annos.Append(rldata)
+			}
 			if err != nil {
 				return nil, "", annos, wrapError(err, "Failed to get group grants")
 			}
@@ -252,7 +381,7 @@ func (r *roleResourceType) Grants(ctx context.Context, resource *v2.Resource, pa
 		}
 	}
 
-	users, rldata, err := r.client.GetUsers(ctx, offset, resourcePageSize)
+	users, rldata, err := r.users.GetUsers(ctx, offset, resourcePageSize)
 	if rldata != nil {
 		annos.Append(rldata)
 	}
@@ -262,12 +391,15 @@ func (r *roleResourceType) Grants(ctx context.Context, resource *v2.Resource, pa
 
 	for _, user := range users.Rows {
 		user := user
-		userResource, err := userResource(ctx, &user)
+		userResource, err := userResource(ctx, &user, accountClassification{})
 		if err != nil {
 			return nil, "", annos, wrapError(err, "Failed to get user resource")
 		}
 
-		grants, err := r.getGrantsFromPermissions(user.Permissions, resource, userResource)
+		grants, rldata, err := r.grantRole(ctx, role, user.Permissions, resource, userResource)
+		if rldata != nil {
+			annos.Append(rldata)
+		}
 		if err != nil {
 			return nil, "", annos, wrapError(err, "Failed to get user grants")
 		}
@@ -287,53 +419,149 @@ func (r *roleResourceType) Grants(ctx context.Context, resource *v2.Resource, pa
 	return rv, nextPage, annos, nil
 }
 
-func (r *roleResourceType) getGrantsFromPermissions(permissions snipeit.Permissions, roleResource *v2.Resource, resource *v2.Resource) ([]*v2.Grant, error) {
-	var rv []*v2.Grant
+// effectiveGrants grants the role to every user that effectively holds it, through its own permissions or
+// one of its groups, and annotates each grant with where it comes from. Groups get no grants of their own.
+func (r *roleResourceType) effectiveGrants(ctx context.Context, resource *v2.Resource, role roleDefinition, bag *pagination.Bag, offset int) ([]*v2.Grant, string, annotations.Annotations, error) {
+	annos := annotations.Annotations{}
+
+	groups, rldata, err := r.users.Groups(ctx)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	if err != nil {
+		return nil, "", annos, wrapError(err, "Failed to get groups")
+	}
+	resolver := snipeit.NewPermissionResolver(groups)
 
-	if isAdminRole(roleResource.Id.Resource) {
-		return grantAdminRole(permissions, roleResource, resource), nil
+	users, rldata, err := r.users.GetUsers(ctx, offset, resourcePageSize)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	if err != nil {
+		return nil, "", annos, wrapError(err, "Failed to get users")
 	}
 
-	for permission, value := range permissions {
-		if value != snipeit.Granted || isRole(permission) {
+	var rv []*v2.Grant
+	for _, user := range users.Rows {
+		user := user
+		principalID, err := rs.NewResourceID(resourceTypeUser, user.ID)
+		if err != nil {
+			return nil, "", annos, wrapError(err, "Failed to get user resource id")
+		}
+
+		permissions := resolver.Effective(&user, role.Permissions)
+		if len(permissions) == 0 || len(permissions) != len(role.Permissions) {
 			continue
 		}
 
-		entitlementName, err := composePermissionEntitlementName(permission)
+		// The change that matters is the one on whoever the permission was granted to, the user or its group.
+		changedID := principalID
+		if permissions[0].Source == snipeit.PermissionSourceGroup {
+			changedID, err = rs.NewResourceID(resourceTypeGroup, permissions[0].GroupID)
+			if err != nil {
+				return nil, "", annos, wrapError(err, "Failed to get group resource id")
+			}
+		}
+
+		change, rldata, err := r.history.Latest(ctx, changedID, role.Permissions...)
+		if rldata != nil {
+			annos.Append(rldata)
+		}
 		if err != nil {
-			return nil, err
+			return nil, "", annos, wrapError(err, "Failed to get permission history")
 		}
 
-		grant := grant.NewGrant(roleResource, entitlementName, resource.Id)
-		rv = append(rv, grant)
+		rv = append(rv, grant.NewGrant(
+			resource,
+			assignedEntitlement,
+			principalID,
+			withPermissionSource(permissions[0]),
+			withLatestChange(change),
+		))
+	}
+
+	if isLastPage(len(users.Rows), resourcePageSize) {
+		return rv, "", annos, nil
+	}
+
+	nextPage, err := handleNextPage(bag, offset+resourcePageSize)
+	if err != nil {
+		return nil, "", annos, err
+	}
+
+	return rv, nextPage, annos, nil
+}
+
+// withPermissionSource records on the grant where the effective permission was granted.
+func withPermissionSource(permission snipeit.EffectivePermission) grant.GrantOption {
+	metadata := map[string]interface{}{
+		"source": string(permission.Source),
+	}
+
+	if permission.GroupID != 0 {
+		metadata["group_id"] = strconv.Itoa(permission.GroupID)
 	}
 
-	return rv, nil
+	if permission.ImpliedBy != "" {
+		metadata["implied_by"] = permission.ImpliedBy
+	}
+
+	return grant.WithGrantMetadata(metadata)
 }
 
-func grantAdminRole(permissions snipeit.Permissions, roleResource *v2.Resource, resource *v2.Resource) []*v2.Grant {
+// grantRole grants the role to the principal if it holds every permission of the role, along with who last
+// changed those permissions when the grant history is enabled.
+func (r *roleResourceType) grantRole(
+	ctx context.Context,
+	role roleDefinition,
+	permissions snipeit.Permissions,
+	roleResource *v2.Resource,
+	resource *v2.Resource,
+) ([]*v2.Grant, *v2.RateLimitDescription, error) {
 	var rv []*v2.Grant
 
-	if isGranted, exists := permissions[strings.ToLower(roleResource.Id.Resource)]; exists && isGranted == snipeit.Granted {
-		grant := grant.NewGrant(roleResource, assignedEntitlement, resource.Id)
-		rv = append(rv, grant)
+	if !role.heldBy(permissions) {
+		return rv, nil, nil
 	}
 
-	return rv
+	change, rldata, err := r.history.Latest(ctx, resource.Id, role.Permissions...)
+	if err != nil {
+		return nil, rldata, err
+	}
+
+	grant := grant.NewGrant(roleResource, assignedEntitlement, resource.Id, withLatestChange(change))
+	rv = append(rv, grant)
+
+	return rv, rldata, nil
 }
 
-func newRoleBuilder(client *snipeit.Client) *roleResourceType {
+func newRoleBuilder(
+	client *snipeit.Client,
+	users *userCache,
+	effectivePermissions bool,
+	config *permissionsConfig,
+	history *grantHistory,
+) *roleResourceType {
 	return &roleResourceType{
-		resourceType: resourceTypeRole,
-		client:       client,
+		resourceType:         resourceTypeRole,
+		client:               client,
+		users:                users,
+		effectivePermissions: effectivePermissions,
+		config:               config,
+		history:              history,
 	}
 }
 
-func (r *roleResourceType) List(ctx context.Context, _ *v2.ResourceId, pagination *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+func (r *roleResourceType) List(ctx context.Context, parentResourceID *v2.ResourceId, pagination *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	// Roles are listed under the app.
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
 	var rv []*v2.Resource
 
-	for _, role := range roles {
-		resource, err := roleResource(ctx, role)
+	for _, role := range r.config.roles() {
+		resource, err := roleResource(ctx, role, rs.WithParentResourceID(parentResourceID))
 		if err != nil {
 			return nil, "", nil, err
 		}
@@ -31,13 +31,16 @@ func getResourceTypeAnnotation() annotations.Annotations {
 type userResourceType struct {
 	resourceType *v2.ResourceType
 	client       *snipeit.Client
+	users        *userCache
+	accounts     *accountClassifier
 }
 
 func (o *userResourceType) ResourceType(ctx context.Context) *v2.ResourceType {
 	return o.resourceType
 }
 
-func userResource(ctx context.Context, user *snipeit.User) (*v2.Resource, error) {
+// userResource builds the resource of the user, account is left unspecified for users listed as grant principals.
+func userResource(ctx context.Context, user *snipeit.User, account accountClassification, opts ...rs.ResourceOption) (*v2.Resource, error) {
 	profile := map[string]interface{}{
 		"first_name":      user.FirstName,
 		"last_name":       user.LastName,
@@ -47,6 +50,27 @@ func userResource(ctx context.Context, user *snipeit.User) (*v2.Resource, error)
 		"vip":             strconv.FormatBool(user.VIP),
 		"activated":       strconv.FormatBool(user.Activated),
 		"employee_number": user.EmployeeNumber,
+		"job_title":       user.JobTitle,
+		"phone":           user.Phone,
+		"remote":          strconv.FormatBool(user.Remote),
+		"ldap_import":     strconv.FormatBool(user.LDAPImport),
+		"two_factor":      strconv.FormatBool(user.TwoFactorEnrolled),
+	}
+
+	for key, reference := range map[string]*snipeit.Reference{
+		"department": user.Department,
+		"location":   user.Location,
+		"company":    user.Company,
+		"manager":    user.Manager,
+	} {
+		if reference != nil {
+			profile[key] = reference.Name
+			profile[key+"_id"] = strconv.Itoa(reference.ID)
+		}
+	}
+
+	if account.reason != "" {
+		profile["service_account_reason"] = account.reason
 	}
 
 	userTraitOptions := []rs.UserTraitOption{
@@ -54,10 +78,27 @@ func userResource(ctx context.Context, user *snipeit.User) (*v2.Resource, error)
 		rs.WithEmail(user.Email, true),
 		rs.WithUserLogin(user.Username),
 		rs.WithStatus(getUserStatus(user)),
+		rs.WithMFAStatus(&v2.UserTrait_MFAStatus{MfaEnabled: user.TwoFactorEnrolled}),
+	}
+
+	if account.accountType != v2.UserTrait_ACCOUNT_TYPE_UNSPECIFIED {
+		userTraitOptions = append(userTraitOptions, rs.WithAccountType(account.accountType))
+	}
+
+	if !user.CreatedAt.IsZero() {
+		userTraitOptions = append(userTraitOptions, rs.WithCreatedAt(user.CreatedAt.Time))
+	}
+
+	if !user.LastLogin.IsZero() {
+		userTraitOptions = append(userTraitOptions, rs.WithLastLogin(user.LastLogin.Time))
+	}
+
+	if user.Avatar.IsUpload() {
+		userTraitOptions = append(userTraitOptions, rs.WithUserIcon(&v2.AssetRef{Id: string(user.Avatar)}))
 	}
 
 	fullName := fmt.Sprintf("%s %s", user.FirstName, user.LastName)
-	resource, err := rs.NewUserResource(fullName, resourceTypeUser, user.ID, userTraitOptions)
+	resource, err := rs.NewUserResource(fullName, resourceTypeUser, user.ID, userTraitOptions, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -73,14 +114,19 @@ func getUserStatus(u *snipeit.User) v2.UserTrait_Status_Status {
 	return v2.UserTrait_Status_STATUS_DISABLED
 }
 
-func (o *userResourceType) List(ctx context.Context, _ *v2.ResourceId, pt *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+func (o *userResourceType) List(ctx context.Context, parentResourceID *v2.ResourceId, pt *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	// Users are listed under the app.
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
 	annos := annotations.Annotations{}
 	bag, offset, err := parsePageToken(pt.Token, &v2.ResourceId{ResourceType: o.resourceType.Id})
 	if err != nil {
 		return nil, "", annos, err
 	}
 
-	users, rldata, err := o.client.GetUsers(ctx, offset, resourcePageSize)
+	users, rldata, err := o.users.GetUsers(ctx, offset, resourcePageSize)
 	if rldata != nil {
 		annos.Append(rldata)
 	}
@@ -88,10 +134,19 @@ func (o *userResourceType) List(ctx context.Context, _ *v2.ResourceId, pt *pagin
 		return nil, "", annos, wrapError(err, "Failed to get users")
 	}
 
+	groups, rldata, err := o.users.Groups(ctx)
+	if rldata != nil {
+		annos.Append(rldata)
+	}
+	if err != nil {
+		return nil, "", annos, wrapError(err, "Failed to get groups")
+	}
+	resolver := snipeit.NewPermissionResolver(groups)
+
 	var resources []*v2.Resource
 	for _, user := range users.Rows {
 		user := user
-		resource, err := userResource(ctx, &user)
+		resource, err := userResource(ctx, &user, o.accounts.classify(&user, resolver), rs.WithParentResourceID(parentResourceID))
 		if err != nil {
 			return nil, "", annos, err
 		}
@@ -100,6 +155,11 @@ func (o *userResourceType) List(ctx context.Context, _ *v2.ResourceId, pt *pagin
 	}
 
 	if isLastPage(len(users.Rows), resourcePageSize) {
+		err = o.users.SaveSyncState(ctx)
+		if err != nil {
+			return nil, "", annos, wrapError(err, "Failed to save user sync state")
+		}
+
 		return resources, "", annos, nil
 	}
 
@@ -119,9 +179,11 @@ func (o *userResourceType) Grants(ctx context.Context, resource *v2.Resource, pT
 	return nil, "", nil, nil
 }
 
-func newUserBuilder(client *snipeit.Client) *userResourceType {
+func newUserBuilder(client *snipeit.Client, users *userCache, accounts *accountClassifier) *userResourceType {
 	return &userResourceType{
 		resourceType: resourceTypeUser,
 		client:       client,
+		users:        users,
+		accounts:     accounts,
 	}
 }
@@ -0,0 +1,98 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	snipeitv1 "github.com/conductorone/baton-snipe-it/pb/snipeit/v1"
+	snipeit "github.com/conductorone/baton-snipe-it/pkg/snipe-it"
+)
+
+// connectorFeature is something the connector does and the permissions the API token needs for it.
+type connectorFeature struct {
+	name        string
+	permissions []string
+	// critical features fail the sync without their permissions.
+	critical bool
+}
+
+// connectorFeatures lists the permissions behind every feature. Snipe-IT only lets superusers list groups and
+// change group memberships.
+var connectorFeatures = []connectorFeature{
+	{name: "user sync", permissions: []string{"users.view"}, critical: true},
+	{name: "group sync", permissions: []string{snipeit.PermissionSuperuser}, critical: true},
+	{name: "role sync", permissions: []string{"users.view", snipeit.PermissionSuperuser}, critical: true},
+	{name: "asset checkout", permissions: []string{"assets.view"}},
+	{name: "group provisioning", permissions: []string{"users.edit", snipeit.PermissionSuperuser}},
+	{name: "events", permissions: []string{"reports.view"}},
+}
+
+// missingPermissions returns the permissions of the feature the token owner doesn't effectively hold.
+func (f connectorFeature) missingPermissions(owner *snipeit.User, resolver *snipeit.PermissionResolver) []string {
+	var missing []string
+	for _, key := range f.permissions {
+		if _, ok := resolver.Resolve(owner, key); !ok {
+			missing = append(missing, key)
+		}
+	}
+
+	return missing
+}
+
+// validatePermissions reports which features the token owner's permissions allow, and fails when a feature the
+// sync depends on isn't. The report is returned even then.
+func (d *SnipeIt) validatePermissions(ctx context.Context, owner *snipeit.User) (*snipeitv1.FeatureReport, error) {
+	l := ctxzap.Extract(ctx)
+
+	// Groups can only be listed by superusers, a token without them is resolved on its own permissions.
+	var groups []snipeit.Group
+	res, _, err := d.client.GetAllGroups(ctx)
+	if err != nil {
+		l.Debug("baton-snipe-it: failed to get groups of the token owner, checking its own permissions only", zap.Error(err))
+	} else {
+		groups = res.Rows
+	}
+	resolver := snipeit.NewPermissionResolver(groups)
+
+	var (
+		usable  []string
+		missing []string
+	)
+	report := &snipeitv1.FeatureReport{Owner: owner.Username}
+	for _, feature := range connectorFeatures {
+		permissions := feature.missingPermissions(owner, resolver)
+		report.Features = append(report.Features, &snipeitv1.Feature{
+			Name:               feature.name,
+			Usable:             len(permissions) == 0,
+			Critical:           feature.critical,
+			MissingPermissions: permissions,
+		})
+		if len(permissions) == 0 {
+			usable = append(usable, feature.name)
+			continue
+		}
+
+		l.Warn(
+			"baton-snipe-it: the API token is missing permissions for a feature",
+			zap.String("feature", feature.name),
+			zap.Strings("missing_permissions", permissions),
+			zap.Bool("critical", feature.critical),
+		)
+
+		if feature.critical {
+			missing = append(missing, fmt.Sprintf("%s (%s)", feature.name, strings.Join(permissions, ", ")))
+		}
+	}
+
+	l.Info("baton-snipe-it: validated API token", zap.String("owner", owner.Username), zap.Strings("usable_features", usable))
+
+	if len(missing) > 0 {
+		return report, fmt.Errorf("snipe-it-connector: the API token of %s lacks permissions needed to sync: %s", owner.Username, strings.Join(missing, "; "))
+	}
+
+	return report, nil
+}
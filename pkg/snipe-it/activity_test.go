@@ -0,0 +1,160 @@
+package snipeit
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestActivityPermissionChanges(t *testing.T) {
+	tests := []struct {
+		name        string
+		logMeta     string
+		wantGranted []string
+		wantRevoked []string
+		wantErr     bool
+	}{
+		{
+			name:    "no permission change",
+			logMeta: `{"email":{"old":"a@example.com","new":"b@example.com"}}`,
+		},
+		{
+			name:    "empty log meta",
+			logMeta: `[]`,
+		},
+		{
+			name:        "permissions logged as json text",
+			logMeta:     `{"permissions":{"old":"{\"users.view\":\"1\",\"assets.view\":\"1\"}","new":"{\"users.view\":\"1\",\"assets.view\":\"-1\",\"reports.view\":\"1\"}"}}`,
+			wantGranted: []string{"reports.view"},
+			wantRevoked: []string{"assets.view"},
+		},
+		{
+			name:        "permissions logged as objects",
+			logMeta:     `{"permissions":{"old":{"users.view":"0"},"new":{"users.view":1,"users.edit":"1"}}}`,
+			wantGranted: []string{"users.edit", "users.view"},
+		},
+		{
+			name:        "no previous permissions",
+			logMeta:     `{"permissions":{"old":null,"new":"{\"superuser\":\"1\"}"}}`,
+			wantGranted: []string{"superuser"},
+		},
+		{
+			name:        "permissions cleared",
+			logMeta:     `{"permissions":{"old":"{\"admin\":\"1\"}","new":""}}`,
+			wantRevoked: []string{"admin"},
+		},
+		{
+			name:    "invalid permissions",
+			logMeta: `{"permissions":{"old":"","new":"not json"}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activity := &Activity{}
+			err := json.Unmarshal([]byte(tt.logMeta), &activity.LogMeta)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			granted, revoked, err := activity.PermissionChanges()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PermissionChanges() error = %v, want an error: %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(granted, tt.wantGranted) || !reflect.DeepEqual(revoked, tt.wantRevoked) {
+				t.Errorf("PermissionChanges() = %v, %v, want %v, %v", granted, revoked, tt.wantGranted, tt.wantRevoked)
+			}
+		})
+	}
+}
+
+func TestActivityGroupChanges(t *testing.T) {
+	tests := []struct {
+		name        string
+		logMeta     string
+		wantAdded   []int
+		wantRemoved []int
+		wantErr     bool
+	}{
+		{
+			name:    "no group change",
+			logMeta: `{"first_name":{"old":"Ann","new":"Anne"}}`,
+		},
+		{
+			name:        "groups logged as ids",
+			logMeta:     `{"groups":{"old":[1,2],"new":[2,3]}}`,
+			wantAdded:   []int{3},
+			wantRemoved: []int{1},
+		},
+		{
+			name:      "groups logged as objects",
+			logMeta:   `{"groups":{"old":[{"id":1,"name":"Staff"}],"new":[{"id":1,"name":"Staff"},{"id":4,"name":"IT"}]}}`,
+			wantAdded: []int{4},
+		},
+		{
+			name:        "groups logged as a comma separated list",
+			logMeta:     `{"groups":{"old":"1, 2","new":""}}`,
+			wantRemoved: []int{1, 2},
+		},
+		{
+			name:      "no previous groups",
+			logMeta:   `{"groups":{"old":null,"new":[5]}}`,
+			wantAdded: []int{5},
+		},
+		{
+			name:    "invalid group",
+			logMeta: `{"groups":{"old":null,"new":[{"name":"IT"}]}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activity := &Activity{}
+			err := json.Unmarshal([]byte(tt.logMeta), &activity.LogMeta)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			added, removed, err := activity.GroupChanges()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GroupChanges() error = %v, want an error: %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(added, tt.wantAdded) || !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("GroupChanges() = %v, %v, want %v, %v", added, removed, tt.wantAdded, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestActivityActor(t *testing.T) {
+	admin := &ActivityItem{ID: 1, Name: "Admin"}
+	createdBy := &ActivityItem{ID: 2, Name: "Created By"}
+
+	tests := []struct {
+		name       string
+		apiVersion APIVersion
+		activity   Activity
+		want       *ActivityItem
+	}{
+		{name: "v6 reads admin", apiVersion: APIVersion6, activity: Activity{Admin: admin, CreatedBy: createdBy}, want: admin},
+		{name: "v7 reads created_by", apiVersion: APIVersion7, activity: Activity{Admin: admin, CreatedBy: createdBy}, want: createdBy},
+		{name: "v6 falls back to created_by", apiVersion: APIVersion6, activity: Activity{CreatedBy: createdBy}, want: createdBy},
+		{name: "v7 falls back to admin", apiVersion: APIVersion7, activity: Activity{Admin: admin}, want: admin},
+		{name: "no actor", apiVersion: APIVersion7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := &Page[Activity]{Rows: []Activity{tt.activity}}
+			page.setAPIVersion(tt.apiVersion)
+
+			if got := page.Rows[0].Actor(); got != tt.want {
+				t.Errorf("Actor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package snipeit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	apiPrefix = "/api/v1"
+
+	maxBaseURLRedirects = 5
+
+	// maxFollowedRedirects is how many redirects a request follows, the limit net/http applies by default.
+	maxFollowedRedirects = 10
+)
+
+// noRedirectsKey marks the context of a request whose redirects are returned instead of followed.
+type noRedirectsKey struct{}
+
+func withoutRedirects(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRedirectsKey{}, true)
+}
+
+// withRedirectControl returns a copy of httpClient that doesn't follow the redirects of requests made
+// withoutRedirects, and follows any other redirect as httpClient would.
+func withRedirectControl(httpClient *http.Client) *http.Client {
+	rv := *httpClient
+	checkRedirect := httpClient.CheckRedirect
+	rv.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if req.Context().Value(noRedirectsKey{}) != nil {
+			return http.ErrUseLastResponse
+		}
+
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+
+		if len(via) >= maxFollowedRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxFollowedRedirects)
+		}
+
+		return nil
+	}
+
+	return &rv
+}
+
+// NormalizeBaseURL switches the client to the URL of the Snipe-IT instance the API paths are joined onto, and
+// returns it. It accepts the address of the instance, with or without a subpath or proxy prefix, trailing slashes
+// or a trailing api/v1, and follows redirects, e.g. from http to https, by asking the instance for its version.
+// A URL that can't be reached, or leads to the web UI instead of the API, is an error. It must be called before
+// the client is used, the probes go through the client's rate limiter like every other request.
+func (c *Client) NormalizeBaseURL(ctx context.Context) (string, error) {
+	l := ctxzap.Extract(ctx)
+	baseUrl := c.baseUrl
+
+	u, err := parseBaseURL(baseUrl)
+	if err != nil {
+		return "", err
+	}
+
+	for i := 0; ; i++ {
+		location, err := c.probeBaseURL(ctx, u)
+		if err != nil {
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				return "", fmt.Errorf("failed to reach the Snipe-IT instance at %s: %w", u, err)
+			}
+
+			return "", err
+		}
+
+		if location == nil {
+			break
+		}
+
+		if i == maxBaseURLRedirects {
+			return "", fmt.Errorf("%s redirects more than %d times", baseUrl, maxBaseURLRedirects)
+		}
+
+		if isLoginPage(location) {
+			return "", loginPageError(baseUrl)
+		}
+
+		if !strings.HasSuffix(location.Path, apiPrefix+"/version") {
+			return "", fmt.Errorf("%s redirects to %s, which is not the Snipe-IT API", baseUrl, location)
+		}
+
+		u = &url.URL{
+			Scheme: location.Scheme,
+			Host:   location.Host,
+			Path:   strings.TrimSuffix(location.Path, apiPrefix+"/version"),
+		}
+	}
+
+	normalized := u.String()
+	if normalized != baseUrl {
+		l.Info("baton-snipe-it: normalized the base URL", zap.String("base_url", baseUrl), zap.String("normalized", normalized))
+	}
+	c.baseUrl = normalized
+
+	return normalized, nil
+}
+
+// parseBaseURL cleans up the URL without asking the instance.
+func parseBaseURL(baseUrl string) (*url.URL, error) {
+	baseUrl = strings.TrimSpace(baseUrl)
+	if !strings.Contains(baseUrl, "://") {
+		baseUrl = "https://" + baseUrl
+	}
+
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %s: %w", baseUrl, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %s: the scheme must be http or https", baseUrl)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %s: missing host", baseUrl)
+	}
+
+	path := strings.TrimRight(u.Path, "/")
+	path = strings.TrimRight(strings.TrimSuffix(path, apiPrefix), "/")
+
+	rv := &url.URL{
+		Scheme: u.Scheme,
+		Host:   u.Host,
+		Path:   path,
+	}
+
+	if isLoginPage(rv) {
+		return nil, loginPageError(baseUrl)
+	}
+
+	return rv, nil
+}
+
+// probeBaseURL asks the instance at u for its version, returning where it redirects to, if anywhere. Redirects
+// are returned rather than followed, so they can be told apart from a redirect to the login page.
+func (c *Client) probeBaseURL(ctx context.Context, u *url.URL) (*url.URL, error) {
+	req, err := c.NewRequest(withoutRedirects(ctx), http.MethodGet, u.JoinPath(apiPrefix, "version"), uhttp.WithAcceptJSONHeader())
+	if err != nil {
+		return nil, err
+	}
+
+	// Any response tells the instance was reached, the status code is looked at below.
+	res, _, err := c.doRequest(req)
+	if res == nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode >= 300 && res.StatusCode < 400:
+		location, err := res.Location()
+		if err != nil {
+			return nil, fmt.Errorf("%s redirects without a location: %w", u, err)
+		}
+
+		return location, nil
+
+	case res.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%s is not a Snipe-IT instance, %s was not found", u, req.URL)
+
+	// The API answers in JSON, even when the token is refused, a page means the request ended up in the web UI.
+	case res.StatusCode < 300 && strings.Contains(res.Header.Get("Content-Type"), "text/html"):
+		return nil, loginPageError(u.String())
+	}
+
+	return nil, nil
+}
+
+func isLoginPage(u *url.URL) bool {
+	return strings.HasSuffix(strings.TrimRight(u.Path, "/"), "/login")
+}
+
+func loginPageError(baseUrl string) error {
+	return fmt.Errorf(
+		"%s leads to the Snipe-IT web UI instead of its API, the base URL should be the address of the instance, e.g. https://snipeit.example.com",
+		baseUrl,
+	)
+}
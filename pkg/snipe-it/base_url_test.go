@@ -0,0 +1,153 @@
+package snipeit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// versionHandler answers the version endpoint of an instance installed under prefix.
+func versionHandler(prefix string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(prefix+apiPrefix+"/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","payload":{"version":"v7.0.13"}}`))
+	})
+
+	return mux
+}
+
+func TestNormalizeBaseURL(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup starts the servers the case needs and returns the base URL to normalize, the expected result
+		// and the HTTP client to use.
+		setup   func(t *testing.T) (baseUrl string, want string, client *http.Client)
+		wantErr string
+	}{
+		{
+			name: "subpath install",
+			setup: func(t *testing.T) (string, string, *http.Client) {
+				srv := httptest.NewServer(versionHandler("/snipeit"))
+				t.Cleanup(srv.Close)
+
+				return srv.URL + "/snipeit/", srv.URL + "/snipeit", srv.Client()
+			},
+		},
+		{
+			name: "trailing api/v1",
+			setup: func(t *testing.T) (string, string, *http.Client) {
+				srv := httptest.NewServer(versionHandler(""))
+				t.Cleanup(srv.Close)
+
+				return srv.URL + "/api/v1/", srv.URL, srv.Client()
+			},
+		},
+		{
+			name: "proxy prefix",
+			setup: func(t *testing.T) (string, string, *http.Client) {
+				srv := httptest.NewServer(versionHandler("/proxy/assets"))
+				t.Cleanup(srv.Close)
+
+				return srv.URL + "/proxy/assets/api/v1", srv.URL + "/proxy/assets", srv.Client()
+			},
+		},
+		{
+			name: "http to https redirect",
+			setup: func(t *testing.T) (string, string, *http.Client) {
+				tlsSrv := httptest.NewTLSServer(versionHandler(""))
+				t.Cleanup(tlsSrv.Close)
+
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					http.Redirect(w, r, tlsSrv.URL+r.URL.Path, http.StatusMovedPermanently)
+				}))
+				t.Cleanup(srv.Close)
+
+				return srv.URL, tlsSrv.URL, tlsSrv.Client()
+			},
+		},
+		{
+			name: "redirect after a throttled probe",
+			setup: func(t *testing.T) (string, string, *http.Client) {
+				tlsSrv := httptest.NewTLSServer(versionHandler(""))
+				t.Cleanup(tlsSrv.Close)
+
+				var throttled atomic.Bool
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if !throttled.Swap(true) {
+						w.Header().Set("Retry-After", "1")
+						w.WriteHeader(http.StatusTooManyRequests)
+						return
+					}
+					http.Redirect(w, r, tlsSrv.URL+r.URL.Path, http.StatusMovedPermanently)
+				}))
+				t.Cleanup(srv.Close)
+
+				return srv.URL, tlsSrv.URL, tlsSrv.Client()
+			},
+		},
+		{
+			name: "redirect to login",
+			setup: func(t *testing.T) (string, string, *http.Client) {
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					http.Redirect(w, r, "/login", http.StatusFound)
+				}))
+				t.Cleanup(srv.Close)
+
+				return srv.URL, "", srv.Client()
+			},
+			wantErr: "web UI",
+		},
+		{
+			name: "html login page",
+			setup: func(t *testing.T) (string, string, *http.Client) {
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+					_, _ = w.Write([]byte(`<html><body><form action="/login"></form></body></html>`))
+				}))
+				t.Cleanup(srv.Close)
+
+				return srv.URL, "", srv.Client()
+			},
+			wantErr: "web UI",
+		},
+		{
+			name: "unreachable host",
+			setup: func(t *testing.T) (string, string, *http.Client) {
+				srv := httptest.NewServer(versionHandler(""))
+				srv.Close()
+
+				return srv.URL, "", srv.Client()
+			},
+			wantErr: "failed to reach",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseUrl, want, client := tt.setup(t)
+
+			c := New(baseUrl, client)
+			got, err := c.NormalizeBaseURL(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NormalizeBaseURL(%q) error = %v, want an error containing %q", baseUrl, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeBaseURL(%q) error = %v", baseUrl, err)
+			}
+
+			if got != want {
+				t.Errorf("NormalizeBaseURL(%q) = %q, want %q", baseUrl, got, want)
+			}
+			if c.BaseURL() != want {
+				t.Errorf("BaseURL() after NormalizeBaseURL(%q) = %q, want %q", baseUrl, c.BaseURL(), want)
+			}
+		})
+	}
+}
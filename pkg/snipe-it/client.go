@@ -2,84 +2,152 @@ package snipeit
 
 import (
 	"context"
-	"encoding/json"
-	"io"
 	"net/http"
 	"net/url"
-	"strings"
+	"sync"
+	"time"
 
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
 )
 
+const defaultPageConcurrency = 4
+
 type (
 	Client struct {
 		uhttp.BaseHttpClient
 
-		baseUrl string
+		baseUrl           string
+		maxAttempts       int
+		requestsPerMinute int
+		maxConcurrency    int
+		limiter           *rateLimiter
+		location          *time.Location
+
+		versionMu  sync.Mutex
+		version    *Version
+		apiVersion APIVersion
 	}
+
+	Option func(*Client)
 )
 
-func New(baseUrl string, httpClient *http.Client) *Client {
-	return &Client{
-		BaseHttpClient: *uhttp.NewBaseHttpClient(httpClient),
+// WithMaxAttempts sets how many times a throttled or temporarily unavailable request is sent before giving up.
+func WithMaxAttempts(maxAttempts int) Option {
+	return func(c *Client) {
+		if maxAttempts > 0 {
+			c.maxAttempts = maxAttempts
+		}
+	}
+}
+
+// WithRequestsPerMinute caps how many requests per minute the client sends. When unset the client
+// follows the limit advertised by the server in the X-RateLimit-Limit header.
+func WithRequestsPerMinute(requestsPerMinute int) Option {
+	return func(c *Client) {
+		c.requestsPerMinute = requestsPerMinute
+	}
+}
+
+// WithMaxConcurrency caps how many requests the client has in flight at once.
+func WithMaxConcurrency(maxConcurrency int) Option {
+	return func(c *Client) {
+		c.maxConcurrency = maxConcurrency
+	}
+}
+
+// WithLocation sets the timezone the instance runs in, APP_TIMEZONE in its .env, which its timestamps are
+// rendered in. Defaults to UTC.
+func WithLocation(loc *time.Location) Option {
+	return func(c *Client) {
+		if loc != nil {
+			c.location = loc
+		}
+	}
+}
+
+// pageConcurrency is how many pages are fetched in parallel when walking a list endpoint.
+func (c *Client) pageConcurrency() int {
+	if c.maxConcurrency > 0 {
+		return c.maxConcurrency
+	}
+
+	return defaultPageConcurrency
+}
+
+func New(baseUrl string, httpClient *http.Client, opts ...Option) *Client {
+	c := &Client{
+		BaseHttpClient: *uhttp.NewBaseHttpClient(withRedirectControl(httpClient)),
 		baseUrl:        baseUrl,
+		maxAttempts:    defaultMaxAttempts,
+		location:       time.UTC,
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	c.limiter = newRateLimiter(c.requestsPerMinute, c.maxConcurrency)
+
+	return c
 }
 
-func (c *Client) Validate(ctx context.Context) error {
-	l := ctxzap.Extract(ctx)
-	stringUrl, err := url.JoinPath(c.baseUrl, "api/v1/users")
+// newRequest builds a JSON request against the Snipe-IT API, joining the path segments onto the base URL.
+func (c *Client) newRequest(ctx context.Context, method string, path []string, options ...uhttp.RequestOption) (*http.Request, error) {
+	stringUrl, err := url.JoinPath(c.baseUrl, path...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	u, err := url.Parse(stringUrl)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	req, err := c.NewRequest(ctx, http.MethodGet, u, uhttp.WithAcceptJSONHeader())
+	options = append([]uhttp.RequestOption{uhttp.WithAcceptJSONHeader()}, options...)
+
+	return c.NewRequest(ctx, method, u, options...)
+}
+
+// doJSON sends the request and, when response is not nil, decodes the JSON body into it.
+func (c *Client) doJSON(req *http.Request, response interface{}) (*v2.RateLimitDescription, error) {
+	var options []uhttp.DoOption
+	if response != nil {
+		options = append(options, uhttp.WithJSONResponse(response))
+	}
+
+	res, rldata, err := c.doRequest(req, options...)
+	if res != nil {
+		defer res.Body.Close()
+	}
 	if err != nil {
-		return err
+		return rldata, err
 	}
 
-	query := []queryFunction{WithOffset(0), WithLimit(1)}
+	if l, ok := response.(localizer); ok {
+		l.localize(c.location)
+	}
+	if v, ok := response.(versioned); ok {
+		v.setAPIVersion(c.APIVersion())
+	}
 
-	addQueryParams(
-		req,
-		query...,
-	)
+	return rldata, nil
+}
 
-	res, err := c.Do(req)
+// Validate fetches the owner of the API token.
+func (c *Client) Validate(ctx context.Context) (*User, error) {
+	l := ctxzap.Extract(ctx)
+
+	user := new(User)
+	_, err := c.get(ctx, user, nil, EndpointCurrentUser)
 	if err != nil {
-		if res != nil {
-			baseUrl := strings.TrimSuffix(c.baseUrl, "/")
-			if res.StatusCode == http.StatusNotFound && strings.HasSuffix(baseUrl, "api/v1") {
-				c.baseUrl = strings.TrimSuffix(baseUrl, "api/v1")
-				return c.Validate(ctx)
-			}
-
-			bodyBytes, err := io.ReadAll(res.Body)
-			if err != nil {
-				return err
-			}
-			bodyString := string(bodyBytes)
-
-			l.Error("Failed to validate API", zap.Error(err), zap.Any("response", res), zap.String("body", bodyString))
-		} else {
-			l.Error("Failed to validate API", zap.Error(err))
-		}
-		return err
+		l.Error("Failed to validate API", zap.Error(err))
+		return nil, err
 	}
-	defer res.Body.Close()
 
-	users := new(UsersResponse)
-	err = json.NewDecoder(res.Body).Decode(users)
-	if err == nil {
-		l.Debug("Got users", zap.Any("users", users))
-	}
+	l.Debug("Got token owner", zap.Int("id", user.ID), zap.String("username", user.Username))
 
-	return err
+	return user, nil
 }
@@ -0,0 +1,63 @@
+package snipeit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+const (
+	EndpointCustomFields = "api/v1/fields"
+
+	customFieldsPageSize = 500
+)
+
+type (
+	// CustomField is the definition of a custom field, shared by every fieldset it belongs to.
+	CustomField struct {
+		ID       int    `json:"id"`
+		Name     string `json:"name"`
+		DBColumn string `json:"db_column_name"`
+		Format   string `json:"format"`
+		Element  string `json:"type"`
+		// Encrypted fields are only readable with assets.view.encrypted_custom_fields.
+		Encrypted bool `json:"field_encrypted"`
+	}
+
+	// CustomFieldValue is the value of a custom field on an item.
+	CustomFieldValue struct {
+		DBColumn string `json:"field"`
+		Value    string `json:"value"`
+		Format   string `json:"field_format"`
+		Element  string `json:"element"`
+	}
+
+	// CustomFields are the custom field values of an item, keyed by field name.
+	CustomFields map[string]CustomFieldValue
+)
+
+// UnmarshalJSON accepts the empty array and null Snipe-IT sends for items without custom fields.
+func (f *CustomFields) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) || bytes.Equal(b, []byte("[]")) {
+		*f = nil
+		return nil
+	}
+
+	var fields map[string]CustomFieldValue
+	err := json.Unmarshal(b, &fields)
+	if err != nil {
+		return err
+	}
+
+	*f = fields
+
+	return nil
+}
+
+// GetCustomFields returns the definition of every custom field.
+func (c *Client) GetCustomFields(ctx context.Context) ([]CustomField, *v2.RateLimitDescription, error) {
+	return All[CustomField](ctx, c, EndpointCustomFields, customFieldsPageSize)
+}
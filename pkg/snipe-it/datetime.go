@@ -0,0 +1,72 @@
+package snipeit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DateTime is a Snipe-IT timestamp, serialized as {"datetime": "2006-01-02 15:04:05", "formatted": "..."}.
+// Snipe-IT renders it in the instance's timezone without an offset, so it is parsed as UTC and moved to the
+// instance's timezone by the client once the response is decoded, see WithLocation.
+type DateTime struct {
+	time.Time
+}
+
+// localizer is implemented by responses holding timestamps, so the client can place them in the instance's
+// timezone.
+type localizer interface {
+	localize(loc *time.Location)
+}
+
+// localize reads the wall clock of the timestamp in loc.
+func (d *DateTime) localize(loc *time.Location) {
+	if d.IsZero() {
+		return
+	}
+
+	t := d.Time
+	d.Time = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+type dateTimeJSON struct {
+	DateTime string `json:"datetime"`
+}
+
+func (d *DateTime) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*d = DateTime{}
+		return nil
+	}
+
+	var raw dateTimeJSON
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		// some endpoints return the bare string
+		err = json.Unmarshal(b, &raw.DateTime)
+		if err != nil {
+			return err
+		}
+	}
+
+	if raw.DateTime == "" {
+		*d = DateTime{}
+		return nil
+	}
+
+	t, err := time.ParseInLocation(dateTimeFormat, raw.DateTime, time.UTC)
+	if err != nil {
+		return err
+	}
+
+	*d = DateTime{Time: t}
+
+	return nil
+}
+
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(dateTimeJSON{DateTime: d.UTC().Format(dateTimeFormat)})
+}
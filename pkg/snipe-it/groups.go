@@ -4,12 +4,13 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"net/url"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 )
 
+const groupsPageSize = 100
+
 type (
 	Group struct {
 		ID          int         `json:"id"`
@@ -23,36 +24,17 @@ type (
 	}
 )
 
+// GetAllGroups pages through every group. Groups are few, so they are returned in a single response.
 func (c *Client) GetAllGroups(ctx context.Context) (*GroupsResponse, *v2.RateLimitDescription, error) {
-	stringUrl, err := url.JoinPath(c.baseUrl, "api/v1/groups")
-	if err != nil {
-		return nil, nil, err
-	}
-
-	u, err := url.Parse(stringUrl)
+	groups, rldata, err := All[Group](ctx, c, EndpointGroups, groupsPageSize)
 	if err != nil {
-		return nil, nil, err
+		return nil, rldata, err
 	}
 
-	req, err := c.NewRequest(ctx, http.MethodGet, u, uhttp.WithAcceptJSONHeader())
-	if err != nil {
-		return nil, nil, err
-	}
-
-	groups := new(GroupsResponse)
-	var rldata v2.RateLimitDescription
-	res, err := c.Do(req, uhttp.WithRatelimitData(&rldata), uhttp.WithJSONResponse(groups))
-	if res != nil {
-		defer res.Body.Close()
-		if res.StatusCode == http.StatusTooManyRequests {
-			return groups, &rldata, nil
-		}
-	}
-	if err != nil {
-		return nil, &rldata, err
-	}
-
-	return groups, &rldata, nil
+	return &GroupsResponse{
+		Total: len(groups),
+		Rows:  groups,
+	}, rldata, nil
 }
 
 func (x GroupsResponse) ContainsGroup(id int) bool {
@@ -65,20 +47,10 @@ func (x GroupsResponse) ContainsGroup(id int) bool {
 	return false
 }
 
-func (c *Client) AddUserToGroup(ctx context.Context, groupId int, userId int) error {
-	user, err := c.GetUser(ctx, userId)
-	if err != nil {
-		return err
-	}
-
-	stringUrl, err := url.JoinPath(c.baseUrl, "api/v1/users", fmt.Sprintf("%d", userId))
+func (c *Client) AddUserToGroup(ctx context.Context, groupId int, userId int) (*v2.RateLimitDescription, error) {
+	user, rldata, err := c.GetUser(ctx, userId)
 	if err != nil {
-		return err
-	}
-
-	u, err := url.Parse(stringUrl)
-	if err != nil {
-		return err
+		return rldata, err
 	}
 
 	var body = PatchUserBody{
@@ -89,34 +61,18 @@ func (c *Client) AddUserToGroup(ctx context.Context, groupId int, userId int) er
 		body.Groups = append(body.Groups, group.ID)
 	}
 
-	req, err := c.NewRequest(ctx, http.MethodPatch, u, uhttp.WithJSONBody(body))
-	if err != nil {
-		return err
-	}
-
-	res, err := c.Do(req)
+	req, err := c.newRequest(ctx, http.MethodPatch, []string{EndpointUsers, fmt.Sprintf("%d", userId)}, uhttp.WithJSONBody(body))
 	if err != nil {
-		return err
+		return rldata, err
 	}
-	defer res.Body.Close()
 
-	return nil
+	return c.doJSON(req, nil)
 }
 
-func (c *Client) RemoveUserFromGroup(ctx context.Context, groupId int, userId int) error {
-	user, err := c.GetUser(ctx, userId)
+func (c *Client) RemoveUserFromGroup(ctx context.Context, groupId int, userId int) (*v2.RateLimitDescription, error) {
+	user, rldata, err := c.GetUser(ctx, userId)
 	if err != nil {
-		return err
-	}
-
-	stringUrl, err := url.JoinPath(c.baseUrl, "api/v1/users", fmt.Sprintf("%d", userId))
-	if err != nil {
-		return err
-	}
-
-	u, err := url.Parse(stringUrl)
-	if err != nil {
-		return err
+		return rldata, err
 	}
 
 	var body = PatchUserBody{
@@ -129,16 +85,10 @@ func (c *Client) RemoveUserFromGroup(ctx context.Context, groupId int, userId in
 		}
 	}
 
-	req, err := c.NewRequest(ctx, http.MethodPatch, u, uhttp.WithJSONBody(body))
-	if err != nil {
-		return err
-	}
-
-	res, err := c.Do(req)
+	req, err := c.newRequest(ctx, http.MethodPatch, []string{EndpointUsers, fmt.Sprintf("%d", userId)}, uhttp.WithJSONBody(body))
 	if err != nil {
-		return err
+		return rldata, err
 	}
-	defer res.Body.Close()
 
-	return nil
+	return c.doJSON(req, nil)
 }
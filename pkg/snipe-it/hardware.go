@@ -0,0 +1,61 @@
+package snipeit
+
+import (
+	"context"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+type (
+	// Hardware is an asset, Snipe-IT's API calls them hardware.
+	Hardware struct {
+		ID           int          `json:"id"`
+		Name         string       `json:"name"`
+		AssetTag     string       `json:"asset_tag"`
+		Serial       string       `json:"serial"`
+		Model        *Reference   `json:"model"`
+		Category     *Reference   `json:"category"`
+		Manufacturer *Reference   `json:"manufacturer"`
+		Company      *Reference   `json:"company"`
+		Location     *Reference   `json:"location"`
+		StatusLabel  *StatusLabel `json:"status_label"`
+		AssignedTo   *Assignee    `json:"assigned_to"`
+		Image        ImageURL     `json:"image"`
+		Requestable  bool         `json:"requestable"`
+		CustomFields CustomFields `json:"custom_fields"`
+		UpdatedAt    DateTime     `json:"updated_at"`
+	}
+
+	// Reference is the {id, name} summary Snipe-IT embeds for related objects.
+	Reference struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	StatusLabel struct {
+		ID         int    `json:"id"`
+		Name       string `json:"name"`
+		StatusType string `json:"status_type"`
+		StatusMeta string `json:"status_meta"`
+	}
+
+	// Assignee is whoever an item is checked out to, a user, a location or another asset depending on Type.
+	Assignee struct {
+		ID       int    `json:"id"`
+		Name     string `json:"name"`
+		Username string `json:"username"`
+		Type     string `json:"type"`
+	}
+)
+
+// AssigneeTypeUser is the type of an Assignee that is a user.
+const AssigneeTypeUser = "user"
+
+func (h *Hardware) localize(loc *time.Location) {
+	h.UpdatedAt.localize(loc)
+}
+
+func (c *Client) GetHardware(ctx context.Context, offset, limit int, query ...QueryFunction) (*Page[Hardware], *v2.RateLimitDescription, error) {
+	return List[Hardware](ctx, c, EndpointHardware, offset, limit, query...)
+}
@@ -0,0 +1,86 @@
+package snipeit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+// ImageURL is the URL of an image, such as a user's avatar or an asset's picture. Snipe-IT sends false or null
+// when there is none, which decode to the empty string.
+type ImageURL string
+
+func (i *ImageURL) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		*i = ""
+		return nil
+	}
+
+	*i = ImageURL(s)
+
+	return nil
+}
+
+// IsUpload reports whether the image was uploaded to the instance, as opposed to e.g. a Gravatar.
+func (i ImageURL) IsUpload() bool {
+	u, err := url.Parse(string(i))
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(u.Path, "/uploads/")
+}
+
+// GetImage downloads an image from the instance and returns its content type along with its content. Only URLs
+// on the instance are fetched, so the access token is never sent anywhere else.
+func (c *Client) GetImage(ctx context.Context, imageUrl string) (string, io.ReadCloser, *v2.RateLimitDescription, error) {
+	base, err := url.Parse(c.baseUrl)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	u, err := base.Parse(imageUrl)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	if u.Scheme != base.Scheme || u.Host != base.Host {
+		return "", nil, nil, fmt.Errorf("snipe-it: refusing to fetch %s, it is not on %s", u.Redacted(), base.Host)
+	}
+
+	req, err := c.NewRequest(ctx, http.MethodGet, u)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	res, rldata, err := c.doRequest(req)
+	if err != nil {
+		if res != nil {
+			_ = res.Body.Close()
+		}
+		return "", nil, rldata, err
+	}
+
+	// The body was already buffered by the http client, closing it can't fail.
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return "", nil, rldata, err
+	}
+
+	contentType := res.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+
+	return contentType, io.NopCloser(bytes.NewReader(body)), rldata, nil
+}
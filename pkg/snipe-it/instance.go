@@ -0,0 +1,181 @@
+package snipeit
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	EndpointVersion  = "api/v1/version"
+	EndpointSettings = "api/v1/settings"
+)
+
+// APIVersion is the major Snipe-IT version the client expects. It decides which permissions the instance offers
+// and under which name renamed fields, such as the actor of an activity, are read. The endpoints used are the
+// same in every tested version.
+type APIVersion int
+
+// versioned is implemented by responses whose fields differ between API versions, so the client can tell them
+// which version the instance speaks once they are decoded.
+type versioned interface {
+	setAPIVersion(version APIVersion)
+}
+
+// The API versions the client is tested against.
+const (
+	APIVersion6 APIVersion = 6
+	APIVersion7 APIVersion = 7
+
+	minAPIVersion = APIVersion6
+	maxAPIVersion = APIVersion7
+)
+
+var majorVersionPattern = regexp.MustCompile(`^v?(\d+)\.`)
+
+type (
+	// Version is the release the instance runs, GET /api/v1/version.
+	Version struct {
+		Version        string `json:"version"`
+		BuildVersion   string `json:"build_version"`
+		HashVersion    string `json:"hash_version"`
+		FullAppVersion string `json:"full_app_version"`
+	}
+
+	versionResponse struct {
+		Payload Version `json:"payload"`
+	}
+
+	// Settings holds the branding of the instance. Reading it requires superuser, and not every release serves
+	// it over the API.
+	Settings struct {
+		SiteName string `json:"site_name"`
+		Logo     string `json:"logo"`
+	}
+)
+
+func (c *Client) GetVersion(ctx context.Context) (*Version, *v2.RateLimitDescription, error) {
+	res := new(versionResponse)
+	rldata, err := c.get(ctx, res, nil, EndpointVersion)
+	if err != nil {
+		return nil, rldata, err
+	}
+
+	return &res.Payload, rldata, nil
+}
+
+func (c *Client) GetSettings(ctx context.Context) (*Settings, *v2.RateLimitDescription, error) {
+	settings := new(Settings)
+	rldata, err := c.get(ctx, settings, nil, EndpointSettings)
+	if err != nil {
+		return nil, rldata, err
+	}
+
+	return settings, rldata, nil
+}
+
+// LogoURL returns the URL of the site logo, which is uploaded to the instance, or the empty string if there is
+// none.
+func (c *Client) LogoURL(settings *Settings) ImageURL {
+	if settings == nil || settings.Logo == "" {
+		return ""
+	}
+
+	return ImageURL(strings.TrimSuffix(c.baseUrl, "/") + "/uploads/" + settings.Logo)
+}
+
+// Major returns the major version of the release, e.g. 7 for "v7.0.13 build 15114 (g4b6ce3bd9)".
+func (v *Version) Major() (int, bool) {
+	match := majorVersionPattern.FindStringSubmatch(strings.TrimSpace(v.Version))
+	if match == nil {
+		return 0, false
+	}
+
+	major, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+
+	return major, true
+}
+
+// DetectVersion asks the instance which release it runs and switches the client to the matching API version.
+// Untested versions are logged and handled as the closest tested one. The version is cached, see Version.
+func (c *Client) DetectVersion(ctx context.Context) (*Version, *v2.RateLimitDescription, error) {
+	l := ctxzap.Extract(ctx)
+
+	version, rldata, err := c.GetVersion(ctx)
+	if err != nil {
+		return nil, rldata, err
+	}
+
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+
+	c.version = version
+
+	major, ok := version.Major()
+	if !ok {
+		l.Warn("baton-snipe-it: unrecognized Snipe-IT version, assuming the oldest supported API",
+			zap.String("version", version.Version),
+			zap.Int("api_version", int(minAPIVersion)),
+		)
+		c.apiVersion = minAPIVersion
+
+		return version, rldata, nil
+	}
+
+	c.apiVersion = APIVersion(major)
+	switch {
+	case c.apiVersion < minAPIVersion:
+		c.apiVersion = minAPIVersion
+	case c.apiVersion > maxAPIVersion:
+		c.apiVersion = maxAPIVersion
+	}
+
+	if c.apiVersion != APIVersion(major) {
+		l.Warn("baton-snipe-it: untested Snipe-IT version, using the closest supported API",
+			zap.String("version", version.Version),
+			zap.Int("api_version", int(c.apiVersion)),
+		)
+	} else {
+		l.Debug("baton-snipe-it: detected Snipe-IT version", zap.String("version", version.Version))
+	}
+
+	return version, rldata, nil
+}
+
+// Version returns the release detected last, detecting it first if it never was.
+func (c *Client) Version(ctx context.Context) (*Version, *v2.RateLimitDescription, error) {
+	c.versionMu.Lock()
+	version := c.version
+	c.versionMu.Unlock()
+
+	if version != nil {
+		return version, nil, nil
+	}
+
+	return c.DetectVersion(ctx)
+}
+
+// APIVersion returns the API version the client expects, v6 until the version was detected.
+func (c *Client) APIVersion() APIVersion {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+
+	if c.apiVersion == 0 {
+		return minAPIVersion
+	}
+
+	return c.apiVersion
+}
+
+// BaseURL returns the URL of the instance.
+func (c *Client) BaseURL() string {
+	return c.baseUrl
+}
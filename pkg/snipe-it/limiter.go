@@ -0,0 +1,120 @@
+package snipeit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+// rateLimiter is a token bucket shared by every request the client sends. When no rate is configured it
+// adopts the limit the server advertises in X-RateLimit-Limit, until then requests are not throttled.
+// It also caps the number of requests in flight when a concurrency limit is set.
+type rateLimiter struct {
+	mu        sync.Mutex
+	perMinute int64
+	fixed     bool
+	tokens    float64
+	last      time.Time
+
+	slots chan struct{}
+}
+
+func newRateLimiter(requestsPerMinute int, maxConcurrency int) *rateLimiter {
+	r := &rateLimiter{}
+
+	if requestsPerMinute > 0 {
+		r.fixed = true
+		r.setRate(int64(requestsPerMinute))
+	}
+
+	if maxConcurrency > 0 {
+		r.slots = make(chan struct{}, maxConcurrency)
+	}
+
+	return r
+}
+
+// acquire blocks until the request may be sent. The returned function must be called once the response
+// has been read to free the concurrency slot.
+func (r *rateLimiter) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if r.slots != nil {
+		select {
+		case r.slots <- struct{}{}:
+			release = func() { <-r.slots }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	for {
+		wait := r.take()
+		if wait <= 0 {
+			return release, nil
+		}
+
+		err := sleep(ctx, wait)
+		if err != nil {
+			release()
+			return nil, err
+		}
+	}
+}
+
+// take consumes a token if one is available, otherwise it returns how long to wait for the next one.
+func (r *rateLimiter) take() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.perMinute <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	r.tokens += now.Sub(r.last).Minutes() * float64(r.perMinute)
+	if burst := r.burst(); r.tokens > burst {
+		r.tokens = burst
+	}
+	r.last = now
+
+	if r.tokens >= 1 {
+		r.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - r.tokens) / float64(r.perMinute) * float64(time.Minute))
+}
+
+// observe adopts the server's advertised limit unless the rate was configured explicitly.
+func (r *rateLimiter) observe(rldata *v2.RateLimitDescription) {
+	if rldata == nil || rldata.Limit <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.fixed || r.perMinute == rldata.Limit {
+		return
+	}
+
+	r.setRate(rldata.Limit)
+}
+
+func (r *rateLimiter) setRate(perMinute int64) {
+	r.perMinute = perMinute
+	r.tokens = r.burst()
+	r.last = time.Now()
+}
+
+// burst allows roughly one second worth of requests at once so a sync can't flood the server's workers.
+func (r *rateLimiter) burst() float64 {
+	burst := float64(r.perMinute) / 60
+	if burst < 1 {
+		return 1
+	}
+
+	return burst
+}
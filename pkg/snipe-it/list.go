@@ -0,0 +1,246 @@
+package snipeit
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+// Paths of the Snipe-IT list endpoints, relative to the base URL.
+const (
+	EndpointUsers       = "api/v1/users"
+	EndpointGroups      = "api/v1/groups"
+	EndpointHardware    = "api/v1/hardware"
+	EndpointLicenses    = "api/v1/licenses"
+	EndpointAccessories = "api/v1/accessories"
+	EndpointConsumables = "api/v1/consumables"
+	EndpointComponents  = "api/v1/components"
+	EndpointCompanies   = "api/v1/companies"
+	EndpointDepartments = "api/v1/departments"
+	EndpointLocations   = "api/v1/locations"
+	EndpointModels      = "api/v1/models"
+	EndpointActivity    = "api/v1/reports/activity"
+)
+
+type (
+	// Page is the {total, rows} envelope every Snipe-IT list endpoint responds with.
+	Page[T any] struct {
+		Total int64 `json:"total"`
+		Rows  []T   `json:"rows"`
+	}
+
+	// Iterator walks every row of a list endpoint page by page. Stop calling Next to stop early.
+	Iterator[T any] struct {
+		client   *Client
+		path     string
+		pageSize int
+		query    []QueryFunction
+
+		offset int
+		rows   []T
+		index  int
+		total  int64
+		last   bool
+		rldata *v2.RateLimitDescription
+		err    error
+	}
+)
+
+func (p *Page[T]) localize(loc *time.Location) {
+	for i := range p.Rows {
+		if row, ok := any(&p.Rows[i]).(localizer); ok {
+			row.localize(loc)
+		}
+	}
+}
+
+func (p *Page[T]) setAPIVersion(version APIVersion) {
+	for i := range p.Rows {
+		if row, ok := any(&p.Rows[i]).(versioned); ok {
+			row.setAPIVersion(version)
+		}
+	}
+}
+
+// List fetches a single page of the list endpoint at path.
+func List[T any](ctx context.Context, c *Client, path string, offset, limit int, query ...QueryFunction) (*Page[T], *v2.RateLimitDescription, error) {
+	page := new(Page[T])
+	// Clip the query, pages fetched in parallel share the caller's slice.
+	query = append(slices.Clip(query), WithOffset(offset), WithLimit(limit))
+	rldata, err := c.get(ctx, page, query, path)
+	if err != nil {
+		return nil, rldata, err
+	}
+
+	return page, rldata, nil
+}
+
+// ForEachPage fetches every page of the list endpoint at path, starting at offset. Once the first page reports
+// the total, the remaining pages are requested in parallel, bounded by the client's concurrency limit.
+// fn is called once per page, never concurrently, but not necessarily in offset order.
+func ForEachPage[T any](
+	ctx context.Context,
+	c *Client,
+	path string,
+	offset int,
+	limit int,
+	fn func(offset int, page *Page[T]) error,
+	query ...QueryFunction,
+) (*v2.RateLimitDescription, error) {
+	first, rldata, err := List[T](ctx, c, path, offset, limit, query...)
+	if err != nil {
+		return rldata, err
+	}
+
+	err = fn(offset, first)
+	if err != nil {
+		return rldata, err
+	}
+
+	if len(first.Rows) < limit {
+		return rldata, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		offsets  = make(chan int)
+	)
+
+	for i := 0; i < c.pageConcurrency(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pageOffset := range offsets {
+				page, pageRldata, err := List[T](ctx, c, path, pageOffset, limit, query...)
+
+				mu.Lock()
+				if pageRldata != nil {
+					rldata = pageRldata
+				}
+				if err == nil && firstErr == nil {
+					err = fn(pageOffset, page)
+				}
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for pageOffset := offset + limit; int64(pageOffset) < first.Total; pageOffset += limit {
+		select {
+		case offsets <- pageOffset:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(offsets)
+	wg.Wait()
+
+	return rldata, firstErr
+}
+
+// NewIterator returns an iterator over every row of the list endpoint at path.
+func NewIterator[T any](c *Client, path string, pageSize int, query ...QueryFunction) *Iterator[T] {
+	return &Iterator[T]{
+		client:   c,
+		path:     path,
+		pageSize: pageSize,
+		query:    query,
+	}
+}
+
+// Next advances to the next row, fetching the next page when needed. It returns false once the rows are
+// exhausted, the context is cancelled or a request fails, see Err.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.index+1 < len(it.rows) {
+		it.index++
+		return true
+	}
+
+	if it.last {
+		return false
+	}
+
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	page, rldata, err := List[T](ctx, it.client, it.path, it.offset, it.pageSize, it.query...)
+	if rldata != nil {
+		it.rldata = rldata
+	}
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.rows = page.Rows
+	it.index = 0
+	it.total = page.Total
+	it.offset += len(page.Rows)
+	it.last = len(page.Rows) < it.pageSize || int64(it.offset) >= page.Total
+
+	return len(it.rows) > 0
+}
+
+// Value returns the current row.
+func (it *Iterator[T]) Value() T {
+	return it.rows[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Total returns the number of rows the server reported for the list.
+func (it *Iterator[T]) Total() int64 {
+	return it.total
+}
+
+// RateLimit returns the rate limit description of the last page fetched.
+func (it *Iterator[T]) RateLimit() *v2.RateLimitDescription {
+	return it.rldata
+}
+
+// All collects every row of the list endpoint at path.
+func All[T any](ctx context.Context, c *Client, path string, pageSize int, query ...QueryFunction) ([]T, *v2.RateLimitDescription, error) {
+	var rv []T
+
+	it := NewIterator[T](c, path, pageSize, query...)
+	for it.Next(ctx) {
+		rv = append(rv, it.Value())
+	}
+
+	return rv, it.RateLimit(), it.Err()
+}
+
+// get fetches a single object, or a page when the query carries an offset and limit.
+func (c *Client) get(ctx context.Context, response interface{}, query []QueryFunction, path ...string) (*v2.RateLimitDescription, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path)
+	if err != nil {
+		return nil, err
+	}
+
+	addQueryParams(req, query...)
+
+	return c.doJSON(req, response)
+}
@@ -3,6 +3,7 @@ package snipeit
 import (
 	"encoding/json"
 	"strconv"
+	"strings"
 )
 
 type Permission string
@@ -38,3 +39,126 @@ var (
 	Denied    Permission = "-1"
 	Inherited Permission = "0"
 )
+
+const (
+	PermissionSuperuser = "superuser"
+	PermissionAdmin     = "admin"
+)
+
+type (
+	// PermissionSource says where an effective permission was granted.
+	PermissionSource string
+
+	EffectivePermission struct {
+		Key    string
+		Source PermissionSource
+		// GroupID is the group the permission, or the role implying it, was granted through.
+		GroupID int
+		// ImpliedBy is the role that implies the permission, if it was not granted directly.
+		ImpliedBy string
+	}
+
+	// PermissionResolver computes which permissions a user effectively holds from the user's own
+	// permissions and those of its groups.
+	PermissionResolver struct {
+		groups map[int]Group
+	}
+)
+
+const (
+	PermissionSourceUser  PermissionSource = "user"
+	PermissionSourceGroup PermissionSource = "group"
+)
+
+// Permission areas that are not guarded by Snipe-IT's item policies, so holding admin does not grant them.
+var notImpliedByAdmin = map[string]bool{
+	PermissionSuperuser: true,
+	PermissionAdmin:     true,
+	"import":            true,
+	"reports":           true,
+	"self":              true,
+}
+
+func NewPermissionResolver(groups []Group) *PermissionResolver {
+	r := &PermissionResolver{
+		groups: make(map[int]Group, len(groups)),
+	}
+
+	for _, group := range groups {
+		r.groups[group.ID] = group
+	}
+
+	return r
+}
+
+// Resolve reports whether the user holds the permission the way Snipe-IT's User::hasAccess decides it:
+// superusers hold everything, admins hold every item permission, otherwise an explicit grant or denial on
+// the user wins over its groups, and any group granting the permission grants it.
+func (r *PermissionResolver) Resolve(user *User, key string) (EffectivePermission, bool) {
+	if superuser, ok := r.check(user, PermissionSuperuser); ok {
+		superuser.Key = key
+		if key != PermissionSuperuser {
+			superuser.ImpliedBy = PermissionSuperuser
+		}
+
+		return superuser, true
+	}
+
+	if key == PermissionSuperuser {
+		return EffectivePermission{}, false
+	}
+
+	if ImpliedByAdmin(key) {
+		if admin, ok := r.check(user, PermissionAdmin); ok {
+			admin.Key = key
+			admin.ImpliedBy = PermissionAdmin
+
+			return admin, true
+		}
+	}
+
+	return r.check(user, key)
+}
+
+// Effective returns the permissions among keys that the user effectively holds.
+func (r *PermissionResolver) Effective(user *User, keys []string) []EffectivePermission {
+	var rv []EffectivePermission
+
+	for _, key := range keys {
+		if permission, ok := r.Resolve(user, key); ok {
+			rv = append(rv, permission)
+		}
+	}
+
+	return rv
+}
+
+// check mirrors User::checkPermissionSection, without the superuser shortcut.
+func (r *PermissionResolver) check(user *User, key string) (EffectivePermission, bool) {
+	switch user.Permissions[key] {
+	case Granted:
+		return EffectivePermission{Key: key, Source: PermissionSourceUser}, true
+	case Denied:
+		return EffectivePermission{}, false
+	}
+
+	for _, userGroup := range user.Groups.Rows {
+		group, ok := r.groups[userGroup.ID]
+		if !ok {
+			continue
+		}
+
+		if group.Permissions[key] == Granted {
+			return EffectivePermission{Key: key, Source: PermissionSourceGroup, GroupID: group.ID}, true
+		}
+	}
+
+	return EffectivePermission{}, false
+}
+
+// ImpliedByAdmin reports whether holding admin grants the permission.
+func ImpliedByAdmin(key string) bool {
+	area, _, _ := strings.Cut(key, ".")
+
+	return !notImpliedByAdmin[area]
+}
@@ -0,0 +1,223 @@
+package snipeit
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPermissionResolverResolve(t *testing.T) {
+	groups := []Group{
+		{ID: 1, Name: "Staff", Permissions: Permissions{"users.view": Granted, "assets.view": Denied}},
+		{ID: 2, Name: "IT", Permissions: Permissions{"assets.view": Granted}},
+		{ID: 3, Name: "Admins", Permissions: Permissions{PermissionAdmin: Granted}},
+		{ID: 4, Name: "Superusers", Permissions: Permissions{PermissionSuperuser: Granted}},
+	}
+
+	member := func(permissions Permissions, groupIDs ...int) *User {
+		user := &User{ID: 10, Permissions: permissions}
+		for _, id := range groupIDs {
+			user.Groups.Rows = append(user.Groups.Rows, Group{ID: id})
+		}
+		user.Groups.Total = len(user.Groups.Rows)
+
+		return user
+	}
+
+	tests := []struct {
+		name   string
+		user   *User
+		key    string
+		want   EffectivePermission
+		wantOk bool
+	}{
+		{
+			name:   "user grant",
+			user:   member(Permissions{"users.view": Granted}),
+			key:    "users.view",
+			want:   EffectivePermission{Key: "users.view", Source: PermissionSourceUser},
+			wantOk: true,
+		},
+		{
+			name:   "group grant",
+			user:   member(nil, 1),
+			key:    "users.view",
+			want:   EffectivePermission{Key: "users.view", Source: PermissionSourceGroup, GroupID: 1},
+			wantOk: true,
+		},
+		{
+			name: "user deny beats group grant",
+			user: member(Permissions{"users.view": Denied}, 1),
+			key:  "users.view",
+		},
+		{
+			name:   "user grant beats group deny",
+			user:   member(Permissions{"assets.view": Granted}, 1),
+			key:    "assets.view",
+			want:   EffectivePermission{Key: "assets.view", Source: PermissionSourceUser},
+			wantOk: true,
+		},
+		{
+			name:   "inherit falls through to the groups",
+			user:   member(Permissions{"users.view": Inherited}, 1),
+			key:    "users.view",
+			want:   EffectivePermission{Key: "users.view", Source: PermissionSourceGroup, GroupID: 1},
+			wantOk: true,
+		},
+		{
+			name:   "any group granting grants",
+			user:   member(nil, 1, 2),
+			key:    "assets.view",
+			want:   EffectivePermission{Key: "assets.view", Source: PermissionSourceGroup, GroupID: 2},
+			wantOk: true,
+		},
+		{
+			name: "unknown group is ignored",
+			user: member(nil, 99),
+			key:  "users.view",
+		},
+		{
+			name:   "superuser implies everything",
+			user:   member(Permissions{PermissionSuperuser: Granted, "reports.view": Denied}),
+			key:    "reports.view",
+			want:   EffectivePermission{Key: "reports.view", Source: PermissionSourceUser, ImpliedBy: PermissionSuperuser},
+			wantOk: true,
+		},
+		{
+			name:   "superuser through a group",
+			user:   member(nil, 4),
+			key:    "users.delete",
+			want:   EffectivePermission{Key: "users.delete", Source: PermissionSourceGroup, GroupID: 4, ImpliedBy: PermissionSuperuser},
+			wantOk: true,
+		},
+		{
+			name:   "superuser itself",
+			user:   member(Permissions{PermissionSuperuser: Granted}),
+			key:    PermissionSuperuser,
+			want:   EffectivePermission{Key: PermissionSuperuser, Source: PermissionSourceUser},
+			wantOk: true,
+		},
+		{
+			name: "superuser is not implied by admin",
+			user: member(Permissions{PermissionAdmin: Granted}),
+			key:  PermissionSuperuser,
+		},
+		{
+			name:   "admin implies item policies",
+			user:   member(Permissions{PermissionAdmin: Granted}),
+			key:    "assets.checkout",
+			want:   EffectivePermission{Key: "assets.checkout", Source: PermissionSourceUser, ImpliedBy: PermissionAdmin},
+			wantOk: true,
+		},
+		{
+			name:   "admin through a group",
+			user:   member(nil, 3),
+			key:    "users.edit",
+			want:   EffectivePermission{Key: "users.edit", Source: PermissionSourceGroup, GroupID: 3, ImpliedBy: PermissionAdmin},
+			wantOk: true,
+		},
+		{
+			name: "admin does not imply reports",
+			user: member(Permissions{PermissionAdmin: Granted}),
+			key:  "reports.view",
+		},
+		{
+			name: "admin does not imply imports",
+			user: member(Permissions{PermissionAdmin: Granted}),
+			key:  "import",
+		},
+		{
+			name: "admin does not imply self service",
+			user: member(Permissions{PermissionAdmin: Granted}),
+			key:  "self.api",
+		},
+		{
+			name: "user deny of admin beats group admin",
+			user: member(Permissions{PermissionAdmin: Denied}, 3),
+			key:  "users.edit",
+		},
+	}
+
+	resolver := NewPermissionResolver(groups)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := resolver.Resolve(tt.user, tt.key)
+			if ok != tt.wantOk {
+				t.Fatalf("Resolve(%q) ok = %v, want %v", tt.key, ok, tt.wantOk)
+			}
+
+			if got != tt.want {
+				t.Errorf("Resolve(%q) = %+v, want %+v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionResolverEffective(t *testing.T) {
+	resolver := NewPermissionResolver([]Group{
+		{ID: 1, Permissions: Permissions{"users.view": Granted, "assets.view": Granted}},
+	})
+	user := &User{
+		Permissions: Permissions{"assets.view": Denied, "licenses.view": Granted},
+		Groups:      GroupsResponse{Total: 1, Rows: []Group{{ID: 1}}},
+	}
+
+	got := resolver.Effective(user, []string{"assets.view", "licenses.view", "users.view", "reports.view"})
+	want := []EffectivePermission{
+		{Key: "licenses.view", Source: PermissionSourceUser},
+		{Key: "users.view", Source: PermissionSourceGroup, GroupID: 1},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Effective() = %+v, want %+v", got, want)
+	}
+}
+
+func TestImpliedByAdmin(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "assets.view", want: true},
+		{key: "users.delete", want: true},
+		{key: "accessories.files", want: true},
+		{key: PermissionSuperuser, want: false},
+		{key: PermissionAdmin, want: false},
+		{key: "import", want: false},
+		{key: "reports.view", want: false},
+		{key: "self.two_factor", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := ImpliedByAdmin(tt.key); got != tt.want {
+				t.Errorf("ImpliedByAdmin(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Permissions
+	}{
+		{name: "strings", json: `{"users.view":"1","assets.view":"-1"}`, want: Permissions{"users.view": Granted, "assets.view": Denied}},
+		{name: "numbers", json: `{"users.view":1,"assets.view":0}`, want: Permissions{"users.view": Granted, "assets.view": Inherited}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Permissions
+			err := json.Unmarshal([]byte(tt.json), &got)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unmarshal %s = %v, want %v", tt.json, got, tt.want)
+			}
+		})
+	}
+}
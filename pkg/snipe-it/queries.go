@@ -3,6 +3,8 @@ package snipeit
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 )
 
 type (
@@ -11,10 +13,44 @@ type (
 		value string
 	}
 
-	queryFunction func() queryParam
+	QueryFunction func() queryParam
+
+	SortOrder    string
+	AssetStatus  string
+	AssignedType string
+)
+
+const (
+	SortAscending  SortOrder = "asc"
+	SortDescending SortOrder = "desc"
+
+	AssetStatusReadyToDeploy AssetStatus = "RTD"
+	AssetStatusDeployed      AssetStatus = "Deployed"
+	AssetStatusPending       AssetStatus = "Pending"
+	AssetStatusUndeployable  AssetStatus = "Undeployable"
+	AssetStatusArchived      AssetStatus = "Archived"
+	AssetStatusRequestable   AssetStatus = "Requestable"
+	AssetStatusDeleted       AssetStatus = "Deleted"
+	AssetStatusBYOD          AssetStatus = "byod"
+
+	AssignedToUser     AssignedType = `App\Models\User`
+	AssignedToAsset    AssignedType = `App\Models\Asset`
+	AssignedToLocation AssignedType = `App\Models\Location`
+
+	// dateTimeFormat is the format Snipe-IT uses for dates in responses.
+	dateTimeFormat = "2006-01-02 15:04:05"
 )
 
-func WithGroupId(groupID int) queryFunction {
+func newQueryParam(name string, value string) QueryFunction {
+	return func() queryParam {
+		return queryParam{
+			name:  name,
+			value: value,
+		}
+	}
+}
+
+func WithGroupId(groupID int) QueryFunction {
 	return func() queryParam {
 		return queryParam{
 			name:  "group_id",
@@ -23,7 +59,7 @@ func WithGroupId(groupID int) queryFunction {
 	}
 }
 
-func WithOffset(offset int) queryFunction {
+func WithOffset(offset int) QueryFunction {
 	return func() queryParam {
 		return queryParam{
 			name:  "offset",
@@ -32,7 +68,7 @@ func WithOffset(offset int) queryFunction {
 	}
 }
 
-func WithLimit(limit int) queryFunction {
+func WithLimit(limit int) QueryFunction {
 	return func() queryParam {
 		return queryParam{
 			name:  "limit",
@@ -41,7 +77,81 @@ func WithLimit(limit int) queryFunction {
 	}
 }
 
-func addQueryParams(req *http.Request, queries ...queryFunction) *http.Request {
+// WithSearch filters the list by Snipe-IT's free text search.
+func WithSearch(search string) QueryFunction {
+	return newQueryParam("search", search)
+}
+
+// WithSort sorts the list by the given column, e.g. "id" or "updated_at".
+func WithSort(column string) QueryFunction {
+	return newQueryParam("sort", column)
+}
+
+func WithOrder(order SortOrder) QueryFunction {
+	return newQueryParam("order", string(order))
+}
+
+func WithCompanyId(companyID int) QueryFunction {
+	return newQueryParam("company_id", strconv.Itoa(companyID))
+}
+
+func WithDepartmentId(departmentID int) QueryFunction {
+	return newQueryParam("department_id", strconv.Itoa(departmentID))
+}
+
+func WithLocationId(locationID int) QueryFunction {
+	return newQueryParam("location_id", strconv.Itoa(locationID))
+}
+
+// WithStatus filters assets by their status label type.
+func WithStatus(status AssetStatus) QueryFunction {
+	return newQueryParam("status", string(status))
+}
+
+// WithDeleted lists soft-deleted records instead of active ones.
+func WithDeleted(deleted bool) QueryFunction {
+	return newQueryParam("deleted", strconv.FormatBool(deleted))
+}
+
+// WithActivated filters users by whether they are allowed to log in.
+func WithActivated(activated bool) QueryFunction {
+	if activated {
+		return newQueryParam("activated", "1")
+	}
+
+	return newQueryParam("activated", "0")
+}
+
+// WithAssignedTo filters checked out items by the ID of what they are assigned to, see WithAssignedType.
+func WithAssignedTo(id int) QueryFunction {
+	return newQueryParam("assigned_to", strconv.Itoa(id))
+}
+
+func WithAssignedType(assignedType AssignedType) QueryFunction {
+	return newQueryParam("assigned_type", string(assignedType))
+}
+
+// WithActionType filters the activity log by action, e.g. ActionUpdate.
+func WithActionType(actionType string) QueryFunction {
+	return newQueryParam("action_type", actionType)
+}
+
+func WithRequestable(requestable bool) QueryFunction {
+	return newQueryParam("requestable", strconv.FormatBool(requestable))
+}
+
+// EncodeQuery renders the query functions as a canonical query string, suitable as a cache key.
+func EncodeQuery(queries ...QueryFunction) string {
+	q := url.Values{}
+	for _, query := range queries {
+		param := query()
+		q.Add(param.name, param.value)
+	}
+
+	return q.Encode()
+}
+
+func addQueryParams(req *http.Request, queries ...QueryFunction) *http.Request {
 	q := req.URL.Query()
 	for _, query := range queries {
 		param := query()
@@ -0,0 +1,201 @@
+package snipeit
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultMaxAttempts = 5
+	minBackoff         = 500 * time.Millisecond
+	maxBackoff         = 30 * time.Second
+
+	// Laravel sends X-RateLimit-Reset as a unix timestamp, anything below this is treated as a number of seconds.
+	unixTimestampThreshold = 1_000_000_000
+)
+
+// doRequest sends the request through the client's rate limiter, retrying throttled and temporarily unavailable
+// responses with jittered exponential backoff. Retry-After and X-RateLimit-Reset headers take precedence over the
+// computed backoff. The returned rate limit description reflects the last response received.
+func (c *Client) doRequest(req *http.Request, options ...uhttp.DoOption) (*http.Response, *v2.RateLimitDescription, error) {
+	ctx := req.Context()
+	l := ctxzap.Extract(ctx)
+
+	var (
+		res     *http.Response
+		rldata  *v2.RateLimitDescription
+		release func()
+		err     error
+	)
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return nil, rldata, err
+			}
+		}
+
+		release, err = c.limiter.acquire(ctx)
+		if err != nil {
+			return nil, rldata, err
+		}
+
+		res, err = c.Do(req, options...)
+		release()
+		if res == nil {
+			return nil, rldata, err
+		}
+		rldata = extractRateLimitData(res)
+		c.limiter.observe(rldata)
+
+		if !isRetryable(res.StatusCode) || attempt >= c.maxAttempts {
+			return res, rldata, err
+		}
+
+		wait := retryDelay(res, attempt)
+		l.Debug(
+			"snipe-it: retrying request",
+			zap.String("method", req.Method),
+			zap.String("url", req.URL.String()),
+			zap.Int("status_code", res.StatusCode),
+			zap.Int("attempt", attempt),
+			zap.Duration("wait", wait),
+		)
+
+		err = sleep(ctx, wait)
+		if err != nil {
+			return res, rldata, err
+		}
+	}
+}
+
+func isRetryable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay honors the server's throttling headers and falls back to full-jitter exponential backoff.
+func retryDelay(res *http.Response, attempt int) time.Duration {
+	if resetIn := throttleResetIn(res.Header); resetIn > 0 {
+		return resetIn + jitter(time.Second)
+	}
+
+	backoff := minBackoff << (attempt - 1)
+	if backoff <= 0 || backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	return backoff/2 + jitter(backoff/2)
+}
+
+func jitter(upTo time.Duration) time.Duration {
+	if upTo <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(upTo))) //nolint:gosec // jitter does not need a cryptographically secure source
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// throttleResetIn returns how long the server asked us to wait, or zero if it did not say.
+func throttleResetIn(header http.Header) time.Duration {
+	if retryAfter := header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.ParseInt(retryAfter, 10, 64); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+
+		if at, err := http.ParseTime(retryAfter); err == nil {
+			return time.Until(at)
+		}
+	}
+
+	if reset := header.Get("X-RateLimit-Reset"); reset != "" {
+		value, err := strconv.ParseInt(reset, 10, 64)
+		if err != nil {
+			return 0
+		}
+
+		if value > unixTimestampThreshold {
+			return time.Until(time.Unix(value, 0))
+		}
+
+		return time.Duration(value) * time.Second
+	}
+
+	return 0
+}
+
+// extractRateLimitData builds a rate limit description from Snipe-IT's throttle headers. Unlike
+// uhttp.WithRatelimitData it understands Laravel's unix timestamp reset header and Retry-After, and
+// it returns nil when the server did not send any rate limit information.
+func extractRateLimitData(res *http.Response) *v2.RateLimitDescription {
+	limit, hasLimit := parseIntHeader(res.Header, "X-RateLimit-Limit")
+	remaining, hasRemaining := parseIntHeader(res.Header, "X-RateLimit-Remaining")
+	resetIn := throttleResetIn(res.Header)
+
+	if !hasLimit && !hasRemaining && resetIn <= 0 && res.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+
+	rldata := &v2.RateLimitDescription{
+		Status:    v2.RateLimitDescription_STATUS_OK,
+		Limit:     limit,
+		Remaining: remaining,
+	}
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		rldata.Status = v2.RateLimitDescription_STATUS_OVERLIMIT
+		rldata.Remaining = 0
+		if resetIn <= 0 {
+			resetIn = time.Minute
+		}
+	}
+
+	if resetIn > 0 {
+		rldata.ResetAt = timestamppb.New(time.Now().Add(resetIn))
+	}
+
+	return rldata
+}
+
+func parseIntHeader(header http.Header, name string) (int64, bool) {
+	value := header.Get(name)
+	if value == "" {
+		return 0, false
+	}
+
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return i, true
+}
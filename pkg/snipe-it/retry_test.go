@@ -0,0 +1,136 @@
+package snipeit
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+func TestThrottleResetIn(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    time.Duration
+	}{
+		{
+			name: "no headers",
+			want: 0,
+		},
+		{
+			name:    "retry-after seconds",
+			headers: map[string]string{"Retry-After": "30"},
+			want:    30 * time.Second,
+		},
+		{
+			name:    "retry-after date",
+			headers: map[string]string{"Retry-After": now.Add(time.Minute).UTC().Format(http.TimeFormat)},
+			want:    time.Minute,
+		},
+		{
+			name:    "retry-after wins over the reset",
+			headers: map[string]string{"Retry-After": "5", "X-RateLimit-Reset": "60"},
+			want:    5 * time.Second,
+		},
+		{
+			name:    "unparseable retry-after falls back to the reset",
+			headers: map[string]string{"Retry-After": "soon", "X-RateLimit-Reset": "60"},
+			want:    time.Minute,
+		},
+		{
+			name:    "reset in seconds",
+			headers: map[string]string{"X-RateLimit-Reset": "45"},
+			want:    45 * time.Second,
+		},
+		{
+			name:    "reset as unix timestamp",
+			headers: map[string]string{"X-RateLimit-Reset": strconv.FormatInt(now.Add(2*time.Minute).Unix(), 10)},
+			want:    2 * time.Minute,
+		},
+		{
+			name:    "invalid reset",
+			headers: map[string]string{"X-RateLimit-Reset": "later"},
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for name, value := range tt.headers {
+				header.Set(name, value)
+			}
+
+			got := throttleResetIn(header)
+			// Dates are only precise to the second and time passes while the test runs.
+			if diff := tt.want - got; diff < 0 || diff > 2*time.Second {
+				t.Errorf("throttleResetIn(%v) = %v, want %v", tt.headers, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractRateLimitData(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		headers    map[string]string
+		want       *v2.RateLimitDescription
+		wantReset  bool
+	}{
+		{
+			name:       "no rate limit headers",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "limit and remaining",
+			statusCode: http.StatusOK,
+			headers:    map[string]string{"X-RateLimit-Limit": "120", "X-RateLimit-Remaining": "118"},
+			want:       &v2.RateLimitDescription{Status: v2.RateLimitDescription_STATUS_OK, Limit: 120, Remaining: 118},
+		},
+		{
+			name:       "throttled",
+			statusCode: http.StatusTooManyRequests,
+			headers:    map[string]string{"X-RateLimit-Limit": "120", "X-RateLimit-Remaining": "3", "Retry-After": "10"},
+			want:       &v2.RateLimitDescription{Status: v2.RateLimitDescription_STATUS_OVERLIMIT, Limit: 120},
+			wantReset:  true,
+		},
+		{
+			name:       "throttled without headers waits a minute",
+			statusCode: http.StatusTooManyRequests,
+			want:       &v2.RateLimitDescription{Status: v2.RateLimitDescription_STATUS_OVERLIMIT},
+			wantReset:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{StatusCode: tt.statusCode, Header: http.Header{}}
+			for name, value := range tt.headers {
+				res.Header.Set(name, value)
+			}
+
+			got := extractRateLimitData(res)
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("extractRateLimitData() = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("extractRateLimitData() = nil, want %v", tt.want)
+			}
+
+			if got.Status != tt.want.Status || got.Limit != tt.want.Limit || got.Remaining != tt.want.Remaining {
+				t.Errorf("extractRateLimitData() = %v, want %v", got, tt.want)
+			}
+			if (got.ResetAt != nil) != tt.wantReset {
+				t.Errorf("extractRateLimitData() reset at = %v, want a reset: %v", got.ResetAt, tt.wantReset)
+			}
+		})
+	}
+}
@@ -3,95 +3,101 @@ package snipeit
 import (
 	"context"
 	"fmt"
-	"net/http"
-	"net/url"
+	"time"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
-	"github.com/conductorone/baton-sdk/pkg/uhttp"
 )
 
+// EndpointCurrentUser returns the owner of the API token.
+const EndpointCurrentUser = "api/v1/users/me"
+
 type (
 	User struct {
-		ID             int            `json:"id"`
-		Username       string         `json:"username"`
-		FirstName      string         `json:"first_name"`
-		LastName       string         `json:"last_name"`
-		Email          string         `json:"email"`
-		VIP            bool           `json:"vip"`
-		EmployeeNumber string         `json:"employee_num"`
-		Activated      bool           `json:"activated"`
-		Groups         GroupsResponse `json:"groups"`
-		Permissions    Permissions    `json:"permissions"`
+		ID             int        `json:"id"`
+		Username       string     `json:"username"`
+		FirstName      string     `json:"first_name"`
+		LastName       string     `json:"last_name"`
+		Email          string     `json:"email"`
+		VIP            bool       `json:"vip"`
+		EmployeeNumber string     `json:"employee_num"`
+		Activated      bool       `json:"activated"`
+		JobTitle       string     `json:"jobtitle"`
+		Phone          string     `json:"phone"`
+		Department     *Reference `json:"department"`
+		Location       *Reference `json:"location"`
+		Company        *Reference `json:"company"`
+		Manager        *Reference `json:"manager"`
+		Remote         bool       `json:"remote"`
+		LDAPImport     bool       `json:"ldap_import"`
+		// TwoFactorEnrolled is whether the user has set up two-factor authentication, TwoFactorActivated
+		// whether it is required of them.
+		TwoFactorEnrolled  bool           `json:"two_factor_enrolled"`
+		TwoFactorActivated bool           `json:"two_factor_activated"`
+		Groups             GroupsResponse `json:"groups"`
+		Permissions        Permissions    `json:"permissions"`
+		Avatar             ImageURL       `json:"avatar"`
+		CreatedAt          DateTime       `json:"created_at"`
+		UpdatedAt          DateTime       `json:"updated_at"`
+		LastLogin          DateTime       `json:"last_login"`
 	}
 
-	UsersResponse struct {
-		Total int64  `json:"total"`
-		Rows  []User `json:"rows"`
-	}
+	UsersResponse = Page[User]
 
 	PatchUserBody struct {
 		Groups []int `json:"groups,omitempty" structs:"groups,omitempty"`
 	}
 )
 
-func (c *Client) GetUsers(ctx context.Context, offset, limit int, query ...queryFunction) (*UsersResponse, *v2.RateLimitDescription, error) {
-	stringUrl, err := url.JoinPath(c.baseUrl, "api/v1/users")
-	if err != nil {
-		return nil, nil, err
-	}
-
-	u, err := url.Parse(stringUrl)
-	if err != nil {
-		return nil, nil, err
-	}
+func (u *User) localize(loc *time.Location) {
+	u.CreatedAt.localize(loc)
+	u.UpdatedAt.localize(loc)
+	u.LastLogin.localize(loc)
+}
 
-	req, err := c.NewRequest(ctx, http.MethodGet, u, uhttp.WithAcceptJSONHeader())
-	if err != nil {
-		return nil, nil, err
-	}
+func (c *Client) GetUsers(ctx context.Context, offset, limit int, query ...QueryFunction) (*UsersResponse, *v2.RateLimitDescription, error) {
+	return List[User](ctx, c, EndpointUsers, offset, limit, query...)
+}
 
-	query = append(query, WithOffset(offset), WithLimit(limit))
+// ForEachUsersPage fetches every page of users matching the query in parallel, see ForEachPage.
+func (c *Client) ForEachUsersPage(
+	ctx context.Context,
+	offset int,
+	limit int,
+	fn func(offset int, users *UsersResponse) error,
+	query ...QueryFunction,
+) (*v2.RateLimitDescription, error) {
+	return ForEachPage[User](ctx, c, EndpointUsers, offset, limit, fn, query...)
+}
 
-	addQueryParams(
-		req,
-		query...,
-	)
+// GetUsersUpdatedSince returns the users changed at or after since, newest first. Deleted lists users soft
+// deleted since then instead. Rows are sorted by updated_at, so the walk stops at the first older user.
+func (c *Client) GetUsersUpdatedSince(ctx context.Context, since time.Time, deleted bool, pageSize int) ([]User, *v2.RateLimitDescription, error) {
+	var rv []User
 
-	var rldata v2.RateLimitDescription
-	users := new(UsersResponse)
-	res, err := c.Do(req, uhttp.WithRatelimitData(&rldata), uhttp.WithJSONResponse(users))
-	if res != nil {
-		defer res.Body.Close()
-	}
-	if err != nil {
-		return nil, &rldata, err
+	query := []QueryFunction{WithSort("updated_at"), WithOrder(SortDescending)}
+	if deleted {
+		query = append(query, WithDeleted(true))
 	}
 
-	return users, &rldata, nil
-}
+	it := NewIterator[User](c, EndpointUsers, pageSize, query...)
+	for it.Next(ctx) {
+		user := it.Value()
+		if user.UpdatedAt.Before(since) {
+			break
+		}
 
-func (c *Client) GetUser(ctx context.Context, id int) (*User, error) {
-	stringUrl, err := url.JoinPath(c.baseUrl, "api/v1/users", fmt.Sprintf("%d", id))
-	if err != nil {
-		return nil, err
+		rv = append(rv, user)
 	}
 
-	u, err := url.Parse(stringUrl)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := c.NewRequest(ctx, http.MethodGet, u, uhttp.WithAcceptJSONHeader())
-	if err != nil {
-		return nil, err
-	}
+	return rv, it.RateLimit(), it.Err()
+}
 
+func (c *Client) GetUser(ctx context.Context, id int) (*User, *v2.RateLimitDescription, error) {
 	user := new(User)
-	res, err := c.Do(req, uhttp.WithJSONResponse(user))
+	rldata, err := c.get(ctx, user, nil, EndpointUsers, fmt.Sprintf("%d", id))
 	if err != nil {
-		return nil, err
+		return nil, rldata, err
 	}
-	defer res.Body.Close()
 
-	return user, nil
+	return user, rldata, nil
 }